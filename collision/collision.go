@@ -1,6 +1,10 @@
 package collision
 
-import rl "github.com/gen2brain/raylib-go/raylib"
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
 
 type Polygon struct {
 	Points [3]rl.Vector2
@@ -9,50 +13,171 @@ type Polygon struct {
 type Hitbox struct {
 	Polygons []Polygon
 	Rotation float32
+	// Layer hitbox belongs to and Mask of layers it collides with, see Layer
+	Layer Layer
+	Mask  Layer
+	// OneWay hitbox blocks only from above, detector still reports all its contacts
+	OneWay bool
+	// Material of surface, nil is DEFAULT_MATERIAL
+	Material *Material
+
+	// Motion, Spin (degrees) and Pivot describe last move of kinematic hitbox
+	Motion rl.Vector2
+	Spin   float32
+	Pivot  rl.Vector2
+
+	revision uint32
+	cache    *shapeCache
+}
+
+// Update replaces hitbox polygons, detectors which track the hitbox
+// with AddDynamicHitbox re-bucket it on next Detect
+func (h *Hitbox) Update(polygons []Polygon) {
+	h.Polygons = polygons
+	h.revision++
+}
+
+// Carry returns displacement of point attached to hitbox during its last move
+func (h Hitbox) Carry(point rl.Vector2) rl.Vector2 {
+	moved := rl.Vector2Add(point, h.Motion)
+	if h.Spin != 0 {
+		sin, cos := math.Sincos(float64(h.Spin) * math.Pi / 180)
+		v := rl.Vector2Subtract(moved, h.Pivot)
+		moved = rl.NewVector2(
+			h.Pivot.X+v.X*float32(cos)-v.Y*float32(sin),
+			h.Pivot.Y+v.X*float32(sin)+v.Y*float32(cos),
+		)
+	}
+	return rl.Vector2Subtract(moved, point)
+}
+
+func (h Hitbox) Bounds() rl.Rectangle {
+	if len(h.Polygons) == 0 {
+		return rl.Rectangle{}
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i, _ := range h.Polygons {
+		for j, _ := range h.Polygons[i].Points {
+			point := h.Polygons[i].Points[j]
+			minX = math.Min(minX, float64(point.X))
+			minY = math.Min(minY, float64(point.Y))
+			maxX = math.Max(maxX, float64(point.X))
+			maxY = math.Max(maxY, float64(point.Y))
+		}
+	}
+
+	return rl.NewRectangle(float32(minX), float32(minY), float32(maxX-minX), float32(maxY-minY))
+}
+
+type dynamicHitbox struct {
+	hitbox   *Hitbox
+	revision uint32
 }
 
 type CollisionDetector struct {
 	Hitboxes []*Hitbox
+
+	grid    *spatialGrid
+	dynamic []dynamicHitbox
 }
 
+// AddHitbox registers hitbox which polygons never change
 func (c *CollisionDetector) AddHitbox(h *Hitbox) {
+	if c.grid == nil {
+		c.grid = newSpatialGrid(GRID_CELL_SIZE)
+	}
 	c.Hitboxes = append(c.Hitboxes, h)
+	c.grid.insert(h)
 }
 
-func (c CollisionDetector) Detect(collider Hitbox) (bool, []map[int]float32) {
-
-	collisions := make([]map[int]float32, 0)
+// AddDynamicHitbox registers hitbox which is moved with Hitbox.Update
+func (c *CollisionDetector) AddDynamicHitbox(h *Hitbox) {
+	c.AddHitbox(h)
+	c.dynamic = append(c.dynamic, dynamicHitbox{
+		hitbox:   h,
+		revision: h.revision,
+	})
+}
 
+func (c *CollisionDetector) RemoveHitbox(h *Hitbox) {
 	for i, _ := range c.Hitboxes {
-		hitbox := c.Hitboxes[i]
-		for j, _ := range hitbox.Polygons {
-
-			polygon := hitbox.Polygons[j]
-			rotation := hitbox.Rotation
-			collisionedPolys := make(map[int]float32, 0)
-			for k, _ := range collider.Polygons {
-				mainPolygon := collider.Polygons[k]
-
-				if rl.CheckCollisionPointTriangle(mainPolygon.Points[0], polygon.Points[0], polygon.Points[1], polygon.Points[2]) {
-					collisionedPolys[k] = rotation
-				}
-				if rl.CheckCollisionPointTriangle(mainPolygon.Points[1], polygon.Points[0], polygon.Points[1], polygon.Points[2]) {
-					collisionedPolys[k] = rotation
-				}
-				if rl.CheckCollisionPointTriangle(mainPolygon.Points[2], polygon.Points[0], polygon.Points[1], polygon.Points[2]) {
-					collisionedPolys[k] = rotation
-				}
-			}
+		if c.Hitboxes[i] == h {
+			c.Hitboxes = append(c.Hitboxes[:i], c.Hitboxes[i+1:]...)
+			break
+		}
+	}
+	for i, _ := range c.dynamic {
+		if c.dynamic[i].hitbox == h {
+			c.dynamic = append(c.dynamic[:i], c.dynamic[i+1:]...)
+			break
+		}
+	}
+	if c.grid != nil {
+		c.grid.remove(h)
+	}
+}
 
-			if len(collisionedPolys) != 0 {
-				collisions = append(collisions, collisionedPolys)
-			}
+// Nearby returns registered hitboxes which bounds overlap rect
+func (c *CollisionDetector) Nearby(rect rl.Rectangle) []*Hitbox {
+	if c == nil || c.grid == nil {
+		return nil
+	}
+	c.syncDynamic()
+	return c.grid.query(rect)
+}
 
+func (c *CollisionDetector) syncDynamic() {
+	for i, _ := range c.dynamic {
+		d := &c.dynamic[i]
+		if d.revision != d.hitbox.revision {
+			c.grid.update(d.hitbox)
+			d.revision = d.hitbox.revision
+		}
+	}
+}
+
+// Detect returns contacts of collider with nearby hitboxes in its mask,
+// concave hitboxes produce a contact for each overlapped triangle
+func (c *CollisionDetector) Detect(collider Hitbox) (bool, []Contact) {
+
+	colliderPoints := make([]rl.Vector2, 0, len(collider.Polygons)*3)
+	for i, _ := range collider.Polygons {
+		colliderPoints = append(colliderPoints, collider.Polygons[i].Points[:]...)
+	}
+	colliderHull := convexHull(colliderPoints)
+	if len(colliderHull) < 3 {
+		return false, nil
+	}
+	colliderShape := newConvexShape(colliderHull)
+
+	contacts := make([]Contact, 0)
+
+	nearby := c.Nearby(collider.Bounds())
+
+	for i, _ := range nearby {
+		hitbox := nearby[i]
+		if !collider.CollidesWith(hitbox) {
+			continue
+		}
+		shapes := hitbox.shapes()
+		for j, _ := range shapes {
+			overlap, normal, depth := collide(colliderShape, shapes[j])
+			if !overlap {
+				continue
+			}
+			contacts = append(contacts, Contact{
+				Hitbox: hitbox,
+				Normal: normal,
+				Depth:  depth,
+				Points: contactPoints(colliderShape, shapes[j]),
+			})
 		}
 	}
 
-	if len(collisions) != 0 {
-		return true, collisions
+	if len(contacts) != 0 {
+		return true, contacts
 	}
 
 	return false, nil
@@ -0,0 +1,166 @@
+package collision
+
+import (
+	"math"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	GRID_CELL_SIZE = 256
+)
+
+type gridCell struct {
+	X, Y int32
+}
+
+type gridEntry struct {
+	hitbox *Hitbox
+	order  int
+	bounds rl.Rectangle
+	cells  []gridCell
+}
+
+// spatialGrid is a uniform grid broad phase, every hitbox is stored
+// in each cell overlapped by its bounding rectangle
+type spatialGrid struct {
+	cellSize  float32
+	cells     map[gridCell][]*gridEntry
+	entries   map[*Hitbox]*gridEntry
+	nextOrder int
+	seen      map[*gridEntry]bool
+}
+
+func newSpatialGrid(cellSize float32) *spatialGrid {
+	return &spatialGrid{
+		cellSize: cellSize,
+		cells:    make(map[gridCell][]*gridEntry),
+		entries:  make(map[*Hitbox]*gridEntry),
+		seen:     make(map[*gridEntry]bool),
+	}
+}
+
+func (g *spatialGrid) insert(h *Hitbox) {
+	if _, ok := g.entries[h]; ok {
+		g.update(h)
+		return
+	}
+
+	entry := &gridEntry{
+		hitbox: h,
+		order:  g.nextOrder,
+	}
+	g.nextOrder++
+	g.entries[h] = entry
+	g.place(entry)
+}
+
+func (g *spatialGrid) remove(h *Hitbox) {
+	entry, ok := g.entries[h]
+	if !ok {
+		return
+	}
+	g.unplace(entry)
+	delete(g.entries, h)
+}
+
+// update moves hitbox to the cells of its current bounds
+func (g *spatialGrid) update(h *Hitbox) {
+	entry, ok := g.entries[h]
+	if !ok {
+		return
+	}
+	g.unplace(entry)
+	g.place(entry)
+}
+
+// query returns hitboxes which bounds overlap rect in insertion order
+func (g *spatialGrid) query(rect rl.Rectangle) []*Hitbox {
+	found := make([]*gridEntry, 0)
+
+	minCell, maxCell := g.cellRange(rect)
+	for x := minCell.X; x <= maxCell.X; x++ {
+		for y := minCell.Y; y <= maxCell.Y; y++ {
+			cellEntries := g.cells[gridCell{x, y}]
+			for i, _ := range cellEntries {
+				entry := cellEntries[i]
+				if g.seen[entry] {
+					continue
+				}
+				g.seen[entry] = true
+				if rectsOverlap(rect, entry.bounds) {
+					found = append(found, entry)
+				}
+			}
+		}
+	}
+
+	for entry := range g.seen {
+		delete(g.seen, entry)
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].order < found[j].order
+	})
+
+	result := make([]*Hitbox, len(found))
+	for i, _ := range found {
+		result[i] = found[i].hitbox
+	}
+	return result
+}
+
+func (g *spatialGrid) place(entry *gridEntry) {
+	entry.cells = entry.cells[:0]
+
+	if len(entry.hitbox.Polygons) == 0 {
+		return
+	}
+
+	entry.bounds = entry.hitbox.Bounds()
+	minCell, maxCell := g.cellRange(entry.bounds)
+	for x := minCell.X; x <= maxCell.X; x++ {
+		for y := minCell.Y; y <= maxCell.Y; y++ {
+			cell := gridCell{x, y}
+			g.cells[cell] = append(g.cells[cell], entry)
+			entry.cells = append(entry.cells, cell)
+		}
+	}
+}
+
+func (g *spatialGrid) unplace(entry *gridEntry) {
+	for i, _ := range entry.cells {
+		cell := entry.cells[i]
+		cellEntries := g.cells[cell]
+		for j, _ := range cellEntries {
+			if cellEntries[j] == entry {
+				cellEntries = append(cellEntries[:j], cellEntries[j+1:]...)
+				break
+			}
+		}
+		if len(cellEntries) == 0 {
+			delete(g.cells, cell)
+		} else {
+			g.cells[cell] = cellEntries
+		}
+	}
+	entry.cells = entry.cells[:0]
+}
+
+func (g *spatialGrid) cellRange(rect rl.Rectangle) (gridCell, gridCell) {
+	return g.cellAt(rect.X, rect.Y), g.cellAt(rect.X+rect.Width, rect.Y+rect.Height)
+}
+
+func (g *spatialGrid) cellAt(x, y float32) gridCell {
+	return gridCell{
+		X: int32(math.Floor(float64(x / g.cellSize))),
+		Y: int32(math.Floor(float64(y / g.cellSize))),
+	}
+}
+
+// rectsOverlap unlike rl.CheckCollisionRecs treats touching rectangles as overlapping
+func rectsOverlap(a, b rl.Rectangle) bool {
+	return a.X <= b.X+b.Width && b.X <= a.X+a.Width &&
+		a.Y <= b.Y+b.Height && b.Y <= a.Y+a.Height
+}
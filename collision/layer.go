@@ -0,0 +1,99 @@
+package collision
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Layer is bitmask of collision layers, hitbox belongs to its Layer and
+// collides with hitboxes which layers are in its Mask
+type Layer uint32
+
+const (
+	LAYER_WORLD Layer = 1 << iota
+	LAYER_PLAYER
+	LAYER_NPC
+	LAYER_TRIGGER
+	LAYER_HAZARD
+	LAYER_PROJECTILE
+
+	LAYER_ALL = LAYER_WORLD | LAYER_PLAYER | LAYER_NPC | LAYER_TRIGGER | LAYER_HAZARD | LAYER_PROJECTILE
+)
+
+// LAYERS lists single layers in order of their bits
+var LAYERS = []Layer{LAYER_WORLD, LAYER_PLAYER, LAYER_NPC, LAYER_TRIGGER, LAYER_HAZARD, LAYER_PROJECTILE}
+
+var layerNames = map[Layer]string{
+	LAYER_WORLD:      "world",
+	LAYER_PLAYER:     "player",
+	LAYER_NPC:        "npc",
+	LAYER_TRIGGER:    "trigger",
+	LAYER_HAZARD:     "hazard",
+	LAYER_PROJECTILE: "projectile",
+}
+
+// String joins names of set layers with "|"
+func (l Layer) String() string {
+	names := make([]string, 0)
+	for i, _ := range LAYERS {
+		if l&LAYERS[i] != 0 {
+			names = append(names, layerNames[LAYERS[i]])
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+func ParseLayer(text string) (Layer, error) {
+	var layer Layer
+	if text == "" {
+		return layer, nil
+	}
+	for _, name := range strings.Split(text, "|") {
+		found := false
+		for l, n := range layerNames {
+			if n == strings.TrimSpace(name) {
+				layer |= l
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown collision layer %q", name)
+		}
+	}
+	return layer, nil
+}
+
+func (l Layer) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Layer) UnmarshalText(text []byte) error {
+	layer, err := ParseLayer(string(text))
+	if err != nil {
+		return err
+	}
+	*l = layer
+	return nil
+}
+
+// layers of hitbox, hitbox without layer is part of world
+func (h Hitbox) layers() Layer {
+	if h.Layer == 0 {
+		return LAYER_WORLD
+	}
+	return h.Layer
+}
+
+// mask of hitbox, hitbox without mask collides with every layer
+func (h Hitbox) mask() Layer {
+	if h.Mask == 0 {
+		return LAYER_ALL
+	}
+	return h.Mask
+}
+
+// CollidesWith reports whether other is in hitbox mask
+func (h Hitbox) CollidesWith(other *Hitbox) bool {
+	return h.mask()&other.layers() != 0
+}
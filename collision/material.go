@@ -0,0 +1,50 @@
+package collision
+
+// Material describes how hitbox surface affects bodies touching it
+type Material struct {
+	Name          string  // preset name, shown in editor
+	Friction      float32 // ground resistance scale
+	Restitution   float32 `json:",omitempty"` // part of landing speed returned as bounce
+	ConveyorSpeed float32 `json:",omitempty"` // pixels per second along surface, positive moves right
+	Sticky        bool    `json:",omitempty"` // catches bodies, no bounce, slow move, weak jump
+}
+
+var DEFAULT_MATERIAL = Material{Name: "default", Friction: 7}
+
+// MATERIALS lists presets cycled in editor, default first
+var MATERIALS = []Material{
+	DEFAULT_MATERIAL,
+	{Name: "ice", Friction: 0.5},
+	{Name: "trampoline", Friction: 7, Restitution: 1},
+	{Name: "conveyor left", Friction: 7, ConveyorSpeed: -120},
+	{Name: "conveyor right", Friction: 7, ConveyorSpeed: 120},
+	{Name: "sticky", Friction: 20, Sticky: true},
+}
+
+// MaterialByName returns preset with name, ok is false for unknown name
+func MaterialByName(name string) (Material, bool) {
+	for i, _ := range MATERIALS {
+		if MATERIALS[i].Name == name {
+			return MATERIALS[i], true
+		}
+	}
+	return Material{}, false
+}
+
+// NextMaterial returns preset following material with same name, wraps to default
+func NextMaterial(m Material) Material {
+	for i, _ := range MATERIALS {
+		if MATERIALS[i].Name == m.Name {
+			return MATERIALS[(i+1)%len(MATERIALS)]
+		}
+	}
+	return DEFAULT_MATERIAL
+}
+
+// SurfaceMaterial returns hitbox material, hitbox without one uses default
+func (h Hitbox) SurfaceMaterial() Material {
+	if h.Material == nil {
+		return DEFAULT_MATERIAL
+	}
+	return *h.Material
+}
@@ -0,0 +1,277 @@
+package collision
+
+import (
+	"math"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// RayHit is first intersection of segment with hitbox
+type RayHit struct {
+	Hitbox   *Hitbox
+	Point    rl.Vector2
+	Normal   rl.Vector2
+	Fraction float32 // 0 at segment start, 1 at its end
+}
+
+// CastHit is first time of impact of shape moved along motion
+type CastHit struct {
+	Hitbox   *Hitbox
+	Normal   rl.Vector2
+	Fraction float32    // part of motion done before impact
+	Offset   rl.Vector2 // shape displacement at impact
+}
+
+// Raycast returns first hitbox in mask crossed by segment from -> to. Segment which
+// starts inside hitbox hits it at its start with normal opposite to segment direction
+func (c *CollisionDetector) Raycast(from, to rl.Vector2, mask Layer) (RayHit, bool) {
+	segment := rl.Vector2Subtract(to, from)
+	nearby := c.Nearby(segmentBounds(from, to))
+
+	best := RayHit{Fraction: float32(math.Inf(1))}
+	found := false
+
+	for i, _ := range nearby {
+		hitbox := nearby[i]
+		if mask&hitbox.layers() == 0 {
+			continue
+		}
+		for j, _ := range hitbox.Polygons {
+			points := hitbox.Polygons[j].Points
+
+			if rl.CheckCollisionPointTriangle(from, points[0], points[1], points[2]) {
+				return RayHit{
+					Hitbox: hitbox,
+					Point:  from,
+					Normal: rl.Vector2Negate(rl.Vector2Normalize(segment)),
+				}, true
+			}
+
+			for k, _ := range points {
+				a, b := points[k], points[(k+1)%3]
+				t, ok := segmentIntersection(from, segment, a, b)
+				if !ok || t >= best.Fraction {
+					continue
+				}
+
+				edge := rl.Vector2Subtract(b, a)
+				normal := rl.Vector2Normalize(rl.NewVector2(-edge.Y, edge.X))
+				if rl.Vector2DotProduct(normal, segment) > 0 {
+					normal = rl.Vector2Negate(normal)
+				}
+
+				best = RayHit{
+					Hitbox:   hitbox,
+					Point:    rl.Vector2Add(from, rl.Vector2Scale(segment, t)),
+					Normal:   normal,
+					Fraction: t,
+				}
+				found = true
+			}
+		}
+	}
+
+	return best, found
+}
+
+// QueryPoint returns hitboxes in mask which contain point
+func (c *CollisionDetector) QueryPoint(point rl.Vector2, mask Layer) []*Hitbox {
+	result := make([]*Hitbox, 0)
+	nearby := c.Nearby(rl.NewRectangle(point.X, point.Y, 0, 0))
+	for i, _ := range nearby {
+		hitbox := nearby[i]
+		if mask&hitbox.layers() == 0 {
+			continue
+		}
+		for j, _ := range hitbox.Polygons {
+			points := hitbox.Polygons[j].Points
+			if rl.CheckCollisionPointTriangle(point, points[0], points[1], points[2]) {
+				result = append(result, hitbox)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// BoxCast sweeps rectangle along motion against hitboxes in mask, see ShapeCast
+func (c *CollisionDetector) BoxCast(rect rl.Rectangle, motion rl.Vector2, mask Layer) (CastHit, bool) {
+	topLeft := rl.NewVector2(rect.X, rect.Y)
+	topRight := rl.NewVector2(rect.X+rect.Width, rect.Y)
+	bottomRight := rl.NewVector2(rect.X+rect.Width, rect.Y+rect.Height)
+	bottomLeft := rl.NewVector2(rect.X, rect.Y+rect.Height)
+
+	return c.ShapeCast(Hitbox{
+		Polygons: []Polygon{
+			{Points: [3]rl.Vector2{topLeft, topRight, bottomRight}},
+			{Points: [3]rl.Vector2{topLeft, bottomLeft, bottomRight}},
+		},
+		Mask: mask,
+	}, motion)
+}
+
+// ShapeCast sweeps convex hull of shape along motion and returns first impact with
+// hitbox in shape mask. Shape which already overlaps hitbox hits it at zero fraction
+// with contact normal
+func (c *CollisionDetector) ShapeCast(shape Hitbox, motion rl.Vector2) (CastHit, bool) {
+	hits := c.CastAll(shape, motion)
+	if len(hits) == 0 {
+		return CastHit{}, false
+	}
+	return hits[0], true
+}
+
+// CastAll is ShapeCast which returns earliest impact with every hitbox on the way,
+// ordered by fraction, so caller can skip hitboxes it passes through
+func (c *CollisionDetector) CastAll(shape Hitbox, motion rl.Vector2) []CastHit {
+	points := make([]rl.Vector2, 0, len(shape.Polygons)*3)
+	for i, _ := range shape.Polygons {
+		points = append(points, shape.Polygons[i].Points[:]...)
+	}
+	hull := convexHull(points)
+	if len(hull) < 3 {
+		return nil
+	}
+	collider := newConvexShape(hull)
+
+	sweptBounds := shape.Bounds()
+	sweptBounds = rectUnion(sweptBounds, rl.NewRectangle(sweptBounds.X+motion.X, sweptBounds.Y+motion.Y, sweptBounds.Width, sweptBounds.Height))
+	nearby := c.Nearby(sweptBounds)
+
+	hits := make([]CastHit, 0)
+
+	for i, _ := range nearby {
+		hitbox := nearby[i]
+		if !shape.CollidesWith(hitbox) {
+			continue
+		}
+
+		best := CastHit{Fraction: float32(math.Inf(1))}
+		found := false
+
+		shapes := hitbox.shapes()
+		for j, _ := range shapes {
+			if overlap, normal, _ := collide(collider, shapes[j]); overlap {
+				best = CastHit{
+					Hitbox: hitbox,
+					Normal: normal,
+				}
+				found = true
+				break
+			}
+
+			t, normal, hit := sweep(collider, shapes[j], motion)
+			if hit && t < best.Fraction {
+				best = CastHit{
+					Hitbox:   hitbox,
+					Normal:   normal,
+					Fraction: t,
+					Offset:   rl.Vector2Scale(motion, t),
+				}
+				found = true
+			}
+		}
+
+		if found {
+			hits = append(hits, best)
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Fraction < hits[j].Fraction
+	})
+
+	return hits
+}
+
+// sweep finds time of impact of collider moving along motion with static shape
+func sweep(collider, shape convexShape, motion rl.Vector2) (float32, rl.Vector2, bool) {
+	enter := float32(math.Inf(-1))
+	exit := float32(math.Inf(1))
+	normal := rl.Vector2{}
+	normalEnter := float32(math.Inf(-1))
+
+	testAxis := func(axis rl.Vector2, candidate bool) bool {
+		colliderMin, colliderMax := collider.project(axis)
+		shapeMin, shapeMax := shape.project(axis)
+		speed := rl.Vector2DotProduct(motion, axis)
+
+		if speed == 0 {
+			return colliderMax >= shapeMin && shapeMax >= colliderMin
+		}
+
+		axisEnter := (shapeMin - colliderMax) / speed
+		axisExit := (shapeMax - colliderMin) / speed
+		if speed < 0 {
+			axisEnter = (shapeMax - colliderMin) / speed
+			axisExit = (shapeMin - colliderMax) / speed
+		}
+
+		if axisEnter > enter {
+			enter = axisEnter
+		}
+		if axisExit < exit {
+			exit = axisExit
+		}
+		if candidate && axisEnter > normalEnter {
+			normalEnter = axisEnter
+			normal = axis
+			if speed > 0 {
+				normal = rl.Vector2Negate(axis)
+			}
+		}
+		return true
+	}
+
+	for i, _ := range shape.axes {
+		if !testAxis(shape.axes[i], !shape.internal[i]) {
+			return 0, rl.Vector2{}, false
+		}
+	}
+	for i, _ := range collider.axes {
+		if !testAxis(collider.axes[i], true) {
+			return 0, rl.Vector2{}, false
+		}
+	}
+
+	if enter > exit || enter > 1 || exit < 0 {
+		return 0, rl.Vector2{}, false
+	}
+	if enter < 0 {
+		enter = 0
+	}
+
+	return enter, normal, true
+}
+
+// segmentIntersection returns fraction of segment from+direction where it crosses a-b
+func segmentIntersection(from, direction, a, b rl.Vector2) (float32, bool) {
+	edge := rl.Vector2Subtract(b, a)
+	denominator := direction.X*edge.Y - direction.Y*edge.X
+	if denominator == 0 {
+		return 0, false
+	}
+
+	diff := rl.Vector2Subtract(a, from)
+	t := (diff.X*edge.Y - diff.Y*edge.X) / denominator
+	u := (diff.X*direction.Y - diff.Y*direction.X) / denominator
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return 0, false
+	}
+	return t, true
+}
+
+func segmentBounds(from, to rl.Vector2) rl.Rectangle {
+	minX := float32(math.Min(float64(from.X), float64(to.X)))
+	minY := float32(math.Min(float64(from.Y), float64(to.Y)))
+	return rl.NewRectangle(minX, minY, float32(math.Abs(float64(to.X-from.X))), float32(math.Abs(float64(to.Y-from.Y))))
+}
+
+func rectUnion(a, b rl.Rectangle) rl.Rectangle {
+	minX := math.Min(float64(a.X), float64(b.X))
+	minY := math.Min(float64(a.Y), float64(b.Y))
+	maxX := math.Max(float64(a.X+a.Width), float64(b.X+b.Width))
+	maxY := math.Max(float64(a.Y+a.Height), float64(b.Y+b.Height))
+	return rl.NewRectangle(float32(minX), float32(minY), float32(maxX-minX), float32(maxY-minY))
+}
@@ -0,0 +1,257 @@
+package collision
+
+import (
+	"math"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	SAT_EPSILON = 0.001
+)
+
+// Contact describes overlap of collider with hitbox. Moving collider
+// along Normal by Depth separates them
+type Contact struct {
+	Hitbox *Hitbox
+	Normal rl.Vector2
+	Depth  float32
+	Points []rl.Vector2
+}
+
+// convexShape is a convex polygon with edge normals. Edges shared by
+// triangles of the same hitbox are internal and never used as contact normal
+type convexShape struct {
+	points   []rl.Vector2
+	axes     []rl.Vector2
+	internal []bool
+}
+
+func newConvexShape(points []rl.Vector2) convexShape {
+	shape := convexShape{
+		points:   points,
+		axes:     make([]rl.Vector2, len(points)),
+		internal: make([]bool, len(points)),
+	}
+	for i, _ := range points {
+		edge := rl.Vector2Subtract(points[(i+1)%len(points)], points[i])
+		shape.axes[i] = rl.Vector2Normalize(rl.NewVector2(-edge.Y, edge.X))
+	}
+	return shape
+}
+
+func (s convexShape) project(axis rl.Vector2) (float32, float32) {
+	min := float32(math.Inf(1))
+	max := float32(math.Inf(-1))
+	for i, _ := range s.points {
+		d := rl.Vector2DotProduct(s.points[i], axis)
+		if d < min {
+			min = d
+		}
+		if d > max {
+			max = d
+		}
+	}
+	return min, max
+}
+
+func (s convexShape) contains(point rl.Vector2) bool {
+	for i, _ := range s.axes {
+		min, max := s.project(s.axes[i])
+		d := rl.Vector2DotProduct(point, s.axes[i])
+		if d < min-SAT_EPSILON || d > max+SAT_EPSILON {
+			return false
+		}
+	}
+	return true
+}
+
+// shapeCache keeps convex shapes of hitbox polygons at revision
+type shapeCache struct {
+	revision uint32
+	polygons *Polygon // first polygon, replaced slice without Update invalidates cache too
+	count    int
+	shapes   []convexShape
+}
+
+// shapes returns cached convex shapes, static hitboxes build them once
+// and kinematic ones after each Update
+func (h *Hitbox) shapes() []convexShape {
+	var first *Polygon
+	if len(h.Polygons) != 0 {
+		first = &h.Polygons[0]
+	}
+	cache := h.cache
+	if cache != nil && cache.revision == h.revision && cache.polygons == first && cache.count == len(h.Polygons) {
+		return cache.shapes
+	}
+
+	h.cache = &shapeCache{
+		revision: h.revision,
+		polygons: first,
+		count:    len(h.Polygons),
+		shapes:   h.buildShapes(),
+	}
+	return h.cache.shapes
+}
+
+// buildShapes splits hitbox into convex shapes, the whole hitbox when
+// its triangles form a convex polygon and the triangles otherwise
+func (h Hitbox) buildShapes() []convexShape {
+	points := make([]rl.Vector2, 0, len(h.Polygons)*3)
+	trianglesArea := float32(0)
+	for i, _ := range h.Polygons {
+		p := h.Polygons[i].Points
+		points = append(points, p[0], p[1], p[2])
+		trianglesArea += triangleArea(p[0], p[1], p[2])
+	}
+
+	hull := convexHull(points)
+	if len(hull) >= 3 && polygonArea(hull)-trianglesArea <= SAT_EPSILON*float32(math.Max(1, float64(trianglesArea))) {
+		return []convexShape{newConvexShape(hull)}
+	}
+
+	shapes := make([]convexShape, 0, len(h.Polygons))
+	for i, _ := range h.Polygons {
+		p := h.Polygons[i].Points
+		if triangleArea(p[0], p[1], p[2]) <= SAT_EPSILON {
+			continue
+		}
+		shape := newConvexShape(p[:])
+		for k, _ := range p {
+			a, b := p[k], p[(k+1)%3]
+			shape.internal[k] = h.sharesEdge(i, a, b)
+		}
+		shapes = append(shapes, shape)
+	}
+	return shapes
+}
+
+func (h Hitbox) sharesEdge(polygonIndex int, a, b rl.Vector2) bool {
+	for i, _ := range h.Polygons {
+		if i == polygonIndex {
+			continue
+		}
+		p := h.Polygons[i].Points
+		for k, _ := range p {
+			c, d := p[k], p[(k+1)%3]
+			if (samePoint(a, c) && samePoint(b, d)) || (samePoint(a, d) && samePoint(b, c)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collide checks collider against shape with separating axis theorem
+func collide(collider, shape convexShape) (bool, rl.Vector2, float32) {
+	bestDepth := float32(math.Inf(1))
+	bestNormal := rl.Vector2{}
+
+	testAxis := func(axis rl.Vector2, candidate bool) bool {
+		colliderMin, colliderMax := collider.project(axis)
+		shapeMin, shapeMax := shape.project(axis)
+		if colliderMax < shapeMin || shapeMax < colliderMin {
+			return false
+		}
+		if !candidate {
+			return true
+		}
+
+		pushPositive := shapeMax - colliderMin
+		pushNegative := colliderMax - shapeMin
+		if pushPositive < bestDepth {
+			bestDepth = pushPositive
+			bestNormal = axis
+		}
+		if pushNegative < bestDepth {
+			bestDepth = pushNegative
+			bestNormal = rl.Vector2Negate(axis)
+		}
+		return true
+	}
+
+	for i, _ := range shape.axes {
+		if !testAxis(shape.axes[i], !shape.internal[i]) {
+			return false, rl.Vector2{}, 0
+		}
+	}
+	for i, _ := range collider.axes {
+		if !testAxis(collider.axes[i], true) {
+			return false, rl.Vector2{}, 0
+		}
+	}
+
+	return true, bestNormal, bestDepth
+}
+
+func contactPoints(collider, shape convexShape) []rl.Vector2 {
+	points := make([]rl.Vector2, 0)
+	for i, _ := range collider.points {
+		if shape.contains(collider.points[i]) {
+			points = append(points, collider.points[i])
+		}
+	}
+	for i, _ := range shape.points {
+		if collider.contains(shape.points[i]) {
+			points = append(points, shape.points[i])
+		}
+	}
+	return points
+}
+
+// convexHull returns hull points in counter clockwise order (monotone chain)
+func convexHull(points []rl.Vector2) []rl.Vector2 {
+	sorted := make([]rl.Vector2, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X == sorted[j].X {
+			return sorted[i].Y < sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+
+	unique := make([]rl.Vector2, 0, len(sorted))
+	for i, _ := range sorted {
+		if len(unique) == 0 || !samePoint(unique[len(unique)-1], sorted[i]) {
+			unique = append(unique, sorted[i])
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	hull := make([]rl.Vector2, 0, len(unique)*2)
+	for i, _ := range unique {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], unique[i]) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, unique[i])
+	}
+	lower := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], unique[i]) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, unique[i])
+	}
+
+	return hull[:len(hull)-1]
+}
+
+func cross(o, a, b rl.Vector2) float32 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+func triangleArea(a, b, c rl.Vector2) float32 {
+	return float32(math.Abs(float64(cross(a, b, c)))) / 2
+}
+
+func polygonArea(points []rl.Vector2) float32 {
+	return float32(math.Abs(float64(signedArea(points))))
+}
+
+func samePoint(a, b rl.Vector2) bool {
+	return math.Abs(float64(a.X-b.X)) <= SAT_EPSILON && math.Abs(float64(a.Y-b.Y)) <= SAT_EPSILON
+}
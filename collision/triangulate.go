@@ -0,0 +1,82 @@
+package collision
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Triangulate splits simple polygon (convex or concave, any winding)
+// into triangles with ear clipping
+func Triangulate(points []rl.Vector2) []Polygon {
+	if len(points) < 3 {
+		return nil
+	}
+
+	indexes := make([]int, len(points))
+	for i, _ := range indexes {
+		indexes[i] = i
+	}
+
+	// ear test below expects counter clockwise order
+	if signedArea(points) < 0 {
+		for i, j := 0, len(indexes)-1; i < j; i, j = i+1, j-1 {
+			indexes[i], indexes[j] = indexes[j], indexes[i]
+		}
+	}
+
+	triangles := make([]Polygon, 0, len(points)-2)
+
+	for len(indexes) > 3 {
+		earFound := false
+		for i, _ := range indexes {
+			prev := points[indexes[(i+len(indexes)-1)%len(indexes)]]
+			curr := points[indexes[i]]
+			next := points[indexes[(i+1)%len(indexes)]]
+
+			if !isEar(points, indexes, prev, curr, next) {
+				continue
+			}
+
+			triangles = append(triangles, Polygon{Points: [3]rl.Vector2{prev, curr, next}})
+			indexes = append(indexes[:i], indexes[i+1:]...)
+			earFound = true
+			break
+		}
+
+		if !earFound {
+			// self intersecting polygon, close the rest with a fan
+			for i := 1; i < len(indexes)-1; i++ {
+				triangles = append(triangles, Polygon{Points: [3]rl.Vector2{points[indexes[0]], points[indexes[i]], points[indexes[i+1]]}})
+			}
+			return triangles
+		}
+	}
+
+	triangles = append(triangles, Polygon{Points: [3]rl.Vector2{points[indexes[0]], points[indexes[1]], points[indexes[2]]}})
+
+	return triangles
+}
+
+func isEar(points []rl.Vector2, indexes []int, prev, curr, next rl.Vector2) bool {
+	if cross(prev, curr, next) <= 0 { // reflex or collinear vertex
+		return false
+	}
+	for i, _ := range indexes {
+		p := points[indexes[i]]
+		if samePoint(p, prev) || samePoint(p, curr) || samePoint(p, next) {
+			continue
+		}
+		if cross(prev, curr, p) >= 0 && cross(curr, next, p) >= 0 && cross(next, prev, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func signedArea(points []rl.Vector2) float32 {
+	area := float32(0)
+	for i, _ := range points {
+		j := (i + 1) % len(points)
+		area += points[i].X*points[j].Y - points[j].X*points[i].Y
+	}
+	return area / 2
+}
@@ -22,6 +22,24 @@ func (w *ObjectContainer) AddObject(obj ...models.Object) {
 	}
 }
 
+func (w *ObjectContainer) InsertObject(index int, obj models.Object) {
+	if index < 0 || index > len(w.objects) {
+		index = len(w.objects)
+	}
+	w.objects = append(w.objects, nil)
+	copy(w.objects[index+1:], w.objects[index:])
+	w.objects[index] = obj
+}
+
+func (w ObjectContainer) IndexOf(obj models.Object) int {
+	for i, _ := range w.objects {
+		if w.objects[i].GetId() == obj.GetId() {
+			return i
+		}
+	}
+	return -1
+}
+
 func (w *ObjectContainer) RemoveObject(obj models.Object) {
 	for i, _ := range w.objects {
 		o := w.objects[i]
@@ -69,7 +87,7 @@ func (w *ObjectContainer) MoveUp(obj models.Object) int {
 	var index int
 	for i, _ := range w.objects {
 		o := w.objects[i]
-		if o == obj {
+		if o.GetId() == obj.GetId() {
 			index = i
 			break
 		}
@@ -92,7 +110,7 @@ func (w *ObjectContainer) MoveDown(obj models.Object) int {
 	var index int
 	for i, _ := range w.objects {
 		o := w.objects[i]
-		if o == obj {
+		if o.GetId() == obj.GetId() {
 			index = i
 			break
 		}
@@ -23,6 +23,17 @@ func (w *ObjectResourceContainer) AddObjectResource(obj... models.ObjectResource
 	}
 }
 
+// RemoveObjectResource drops obj from resources and objects, caller unloads it
+func (w *ObjectResourceContainer) RemoveObjectResource(obj models.ObjectResource) {
+	for i, _ := range w.objectResources {
+		if w.objectResources[i] == obj {
+			w.objectResources = append(w.objectResources[:i], w.objectResources[i+1:]...)
+			break
+		}
+	}
+	w.RemoveObject(obj)
+}
+
 func (w ObjectResourceContainer) Load() {
 	for _, o := range w.objectResources {
 		o.Load()
@@ -19,6 +19,7 @@ type AnimationType int
 const (
 	Loop AnimationType = iota
 	Temporary
+	Once // plays once and holds last frame
 )
 
 var (
@@ -81,6 +82,10 @@ func (a Animation) Draw() {
 	rl.DrawTextureRec(a.Texture, a.frame, a.Pos, rl.White)
 }
 
+func (a Animation) DrawTinted(tint rl.Color) {
+	rl.DrawTextureRec(a.Texture, a.frame, a.Pos, tint)
+}
+
 func (a *Animation) Update(delta float32) {
 
 	if a.Orientation == Left {
@@ -116,6 +121,13 @@ func (a *Animation) Update(delta float32) {
 
 	}
 
+	if a.animationType == Once {
+		if a.framesCounter >= FPS/(a.framesPerSecond*int32(a.animationSpeed)) && a.currentFrame < a.steps-1 {
+			a.framesCounter = 0
+			a.currentFrame++
+		}
+	}
+
 	a.frame.X = float32(a.currentFrame) * float32(a.StepInPixel)
 	if a.reverse {
 		mirrorCurrentFrame := a.steps - a.currentFrame - 1
@@ -127,6 +139,14 @@ func (a *Animation) Reverse(rev bool) {
 	a.reverse = rev
 }
 
+// Progress is part of animation frames already played, 0..1
+func (a Animation) Progress() float32 {
+	if a.steps == 0 {
+		return 1
+	}
+	return float32(a.currentFrame+1) / float32(a.steps)
+}
+
 func (a *Animation) Stop() {
 	a.currentFrame = 0
 	a.frame.X = float32(a.StepInPixel)
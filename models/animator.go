@@ -0,0 +1,274 @@
+package models
+
+import (
+	"ahasuerus/resources"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const ANY_ANIMATION_STATE = "*"
+
+// AnimationState is animation played while animator is in state
+type AnimationState struct {
+	Name            string
+	Texture         resources.GameTexture
+	Frames          int32
+	Type            string  // loop, temporary or once
+	FramesPerSecond int32   `json:",omitempty"` // loop and once animations
+	Seconds         float32 `json:",omitempty"` // temporary animations
+}
+
+// AnimationTransition moves animator From state (or any state with "*") To other
+// state when all When conditions hold. Condition is parameter name which must be
+// non-zero, "!name" which must be zero, or comparison "name>value", "name<value"
+type AnimationTransition struct {
+	From     string
+	To       string
+	When     []string
+	ExitTime float32 `json:",omitempty"` // part of From animation played before leaving it, 0..1
+	Blend    float32 `json:",omitempty"` // seconds From animation fades out over To
+}
+
+// AnimationGraph is animation state machine definition, transitions are checked in order
+type AnimationGraph struct {
+	Initial     string
+	States      []AnimationState
+	Transitions []AnimationTransition
+}
+
+// AnimationParams are condition values supplied by animated object each frame, bools are 0 or 1
+type AnimationParams map[string]float32
+
+func LoadAnimationGraph(path resources.AnimationGraph) (AnimationGraph, error) {
+	var graph AnimationGraph
+	data, err := os.ReadFile(string(path))
+	if err != nil {
+		return graph, err
+	}
+	if err := json.Unmarshal(data, &graph); err != nil {
+		return graph, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return graph, nil
+}
+
+type animationCondition struct {
+	param  string
+	op     byte // 0 for truth test
+	negate bool
+	value  float32
+}
+
+func parseAnimationCondition(text string) (animationCondition, error) {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexAny(text, "<>"); i > 0 {
+		value, err := strconv.ParseFloat(strings.TrimSpace(text[i+1:]), 32)
+		if err != nil {
+			return animationCondition{}, fmt.Errorf("condition %q: %w", text, err)
+		}
+		return animationCondition{
+			param: strings.TrimSpace(text[:i]),
+			op:    text[i],
+			value: float32(value),
+		}, nil
+	}
+	if strings.HasPrefix(text, "!") {
+		return animationCondition{param: text[1:], negate: true}, nil
+	}
+	return animationCondition{param: text}, nil
+}
+
+func (c animationCondition) holds(params AnimationParams) bool {
+	value := params[c.param]
+	switch c.op {
+	case '>':
+		return value > c.value
+	case '<':
+		return value < c.value
+	}
+	return (value != 0) != c.negate
+}
+
+type animationTransition struct {
+	AnimationTransition
+	conditions []animationCondition
+}
+
+// Animator plays animation of current state of graph
+type Animator struct {
+	graph       AnimationGraph
+	animations  map[string]*Animation
+	transitions []animationTransition
+	state       string
+
+	previous  *Animation // fading out animation of blended transition
+	blend     float32
+	blendTime float32
+}
+
+func NewAnimator(graph AnimationGraph) (*Animator, error) {
+	a := &Animator{
+		graph:      graph,
+		animations: make(map[string]*Animation),
+		state:      graph.Initial,
+	}
+
+	for i, _ := range graph.States {
+		state := graph.States[i]
+		var animation *Animation
+		if state.Frames <= 0 {
+			return nil, fmt.Errorf("animation state %s: Frames must be positive", state.Name)
+		}
+		if (state.Type == "loop" || state.Type == "once") && state.FramesPerSecond <= 0 {
+			return nil, fmt.Errorf("animation state %s: FramesPerSecond must be positive", state.Name)
+		}
+		if state.Type == "temporary" && state.Seconds <= 0 {
+			return nil, fmt.Errorf("animation state %s: Seconds must be positive", state.Name)
+		}
+		switch state.Type {
+		case "loop":
+			animation = NewAnimation(state.Texture, state.Frames, Loop).FramesPerSecond(state.FramesPerSecond)
+		case "temporary":
+			animation = NewAnimation(state.Texture, state.Frames, Temporary).TimeInSeconds(state.Seconds)
+		case "once":
+			animation = NewAnimation(state.Texture, state.Frames, Once).FramesPerSecond(state.FramesPerSecond)
+		default:
+			return nil, fmt.Errorf("animation state %s: unknown type %q", state.Name, state.Type)
+		}
+		a.animations[state.Name] = animation
+	}
+
+	if _, ok := a.animations[graph.Initial]; !ok {
+		return nil, fmt.Errorf("initial animation state %q not found", graph.Initial)
+	}
+
+	for i, _ := range graph.Transitions {
+		transition := animationTransition{AnimationTransition: graph.Transitions[i]}
+		if _, ok := a.animations[transition.From]; !ok && transition.From != ANY_ANIMATION_STATE {
+			return nil, fmt.Errorf("transition from unknown animation state %q", transition.From)
+		}
+		if _, ok := a.animations[transition.To]; !ok {
+			return nil, fmt.Errorf("transition to unknown animation state %q", transition.To)
+		}
+		for _, text := range transition.When {
+			condition, err := parseAnimationCondition(text)
+			if err != nil {
+				return nil, err
+			}
+			transition.conditions = append(transition.conditions, condition)
+		}
+		a.transitions = append(a.transitions, transition)
+	}
+
+	return a, nil
+}
+
+func (a *Animator) Load() {
+	for _, animation := range a.animations {
+		animation.Load()
+	}
+}
+
+// Unload releases textures once, states may share texture
+func (a *Animator) Unload() {
+	unloaded := make(map[resources.GameTexture]bool)
+	for _, animation := range a.animations {
+		if !unloaded[animation.GameTexture] {
+			animation.Unload()
+			unloaded[animation.GameTexture] = true
+		}
+	}
+}
+
+func (a Animator) State() string {
+	return a.state
+}
+
+func (a Animator) Current() *Animation {
+	return a.animations[a.state]
+}
+
+// Restore switches state without restarting animation or blending, used by rewind
+func (a *Animator) Restore(state string) {
+	if _, ok := a.animations[state]; ok {
+		a.state = state
+		a.previous = nil
+	}
+}
+
+// Reset starts initial state
+func (a *Animator) Reset() {
+	a.Restore(a.graph.Initial)
+	a.Current().Begin()
+}
+
+// Evaluate follows first transition from current state which conditions hold
+func (a *Animator) Evaluate(params AnimationParams) {
+	current := a.Current()
+	for i, _ := range a.transitions {
+		transition := a.transitions[i]
+		if transition.From != ANY_ANIMATION_STATE && transition.From != a.state {
+			continue
+		}
+		if transition.ExitTime > 0 && current.Progress() < transition.ExitTime {
+			continue
+		}
+		if !transition.matches(params) {
+			continue
+		}
+		if transition.To == a.state {
+			return
+		}
+
+		// returning to animation still fading out ends blend, it would be drawn twice
+		fading := a.previous
+		a.previous = nil
+		if transition.Blend > 0 && a.animations[transition.To] != fading && a.animations[transition.To] != current {
+			a.previous = current
+			a.blend = transition.Blend
+			a.blendTime = transition.Blend
+		}
+		a.state = transition.To
+		a.Current().Begin()
+		return
+	}
+}
+
+func (t animationTransition) matches(params AnimationParams) bool {
+	for i, _ := range t.conditions {
+		if !t.conditions[i].holds(params) {
+			return false
+		}
+	}
+	return true
+}
+
+// Update moves current and fading animation to pos
+func (a *Animator) Update(delta float32, pos rl.Vector2, orientation Orientation, speed uint8) {
+	if a.previous != nil {
+		a.blend -= delta
+		if a.blend <= 0 {
+			a.previous = nil
+		} else {
+			a.previous.Pos = pos
+			a.previous.Orientation = orientation
+		}
+	}
+
+	current := a.Current()
+	current.Pos = pos
+	current.Orientation = orientation
+	current.AnimationSpeed(speed)
+	current.Update(delta)
+}
+
+func (a Animator) Draw() {
+	if a.previous != nil {
+		a.previous.DrawTinted(rl.Fade(rl.White, a.blend/a.blendTime))
+	}
+	a.Current().Draw()
+}
@@ -29,6 +29,26 @@ func NewBaseEditorItem(polygons [2]collision.Polygon) BaseEditorItem {
 	}
 }
 
+func RectanglePolygons(topLeft rl.Vector2, width, height float32) [2]collision.Polygon {
+	bottomLeft := rl.Vector2{topLeft.X, topLeft.Y + height}
+
+	topRight := rl.Vector2{topLeft.X + width, topLeft.Y}
+	bottomRight := rl.Vector2{topLeft.X + width, topLeft.Y + height}
+
+	return [2]collision.Polygon{
+		{
+			Points: [3]rl.Vector2{
+				topLeft, topRight, bottomRight,
+			},
+		},
+		{
+			Points: [3]rl.Vector2{
+				topLeft, bottomLeft, bottomRight,
+			},
+		},
+	}
+}
+
 func (p *BaseEditorItem) GetId() string {
 	return p.Id
 }
@@ -37,6 +57,22 @@ func (p *BaseEditorItem) GetDrawIndex() int {
 	return p.DrawIndex
 }
 
+func (p *BaseEditorItem) GetBaseEditorItem() *BaseEditorItem {
+	return p
+}
+
+func (p BaseEditorItem) GetState() EditorItemState {
+	return EditorItemState{
+		Polygons: p.Polygons,
+		Rotation: p.Rotation,
+	}
+}
+
+func (p *BaseEditorItem) SetState(state EditorItemState) {
+	p.Polygons = state.Polygons
+	p.Rotation = state.Rotation
+}
+
 func (p *BaseEditorItem) SetEditorMoveWithCursorTrue() {
 	p.EditorMoveWithCursor = true
 }
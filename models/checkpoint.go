@@ -0,0 +1,85 @@
+package models
+
+import (
+	"ahasuerus/collision"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	CHECKPOINT_POLE_HEIGHT = 120
+	CHECKPOINT_FLAG_SIZE   = 40
+)
+
+var reachedCheckpoints = make([]*Checkpoint, 0)
+
+// PollReachedCheckpoints returns checkpoints touched by player since last call
+func PollReachedCheckpoints() []*Checkpoint {
+	if len(reachedCheckpoints) == 0 {
+		return nil
+	}
+	reached := reachedCheckpoints
+	reachedCheckpoints = make([]*Checkpoint, 0)
+	return reached
+}
+
+// Checkpoint records respawn point when player enters its volume,
+// player respawns at its top left corner
+type Checkpoint struct {
+	CollisionHitbox
+
+	Active bool `json:"-"`
+	inside bool `json:"-"`
+}
+
+func (p *Checkpoint) Draw() {
+	color := rl.Gray
+	if p.Active {
+		color = rl.Lime
+	}
+
+	base := rl.NewVector2(p.Center().X, p.BottomRight().Y)
+	top := rl.NewVector2(base.X, base.Y-CHECKPOINT_POLE_HEIGHT)
+	rl.DrawLineEx(base, top, 6, rl.LightGray)
+	rl.DrawTriangle(
+		top,
+		rl.NewVector2(top.X, top.Y+CHECKPOINT_FLAG_SIZE),
+		rl.NewVector2(top.X+CHECKPOINT_FLAG_SIZE*1.5, top.Y+CHECKPOINT_FLAG_SIZE/2),
+		color,
+	)
+
+	if DRAW_MODELS {
+		polys := p.PolygonsWithRotation()
+		for i, _ := range polys {
+			rl.DrawTriangleLines(
+				polys[i].Points[0],
+				polys[i].Points[1],
+				polys[i].Points[2],
+				rl.Lime,
+			)
+		}
+	}
+
+	p.BaseEditorItem.Draw()
+}
+
+func (p *Checkpoint) Update(delta float32) {
+	if rl.IsKeyDown(rl.KeyLeftShift) { // rewind does not reach checkpoints
+		return
+	}
+
+	detected, _ := p.CollisionProcessor.Detect(collision.Hitbox{
+		Polygons: p.PolygonsWithRotation(),
+		Mask:     collision.LAYER_PLAYER,
+	})
+
+	if detected && !p.inside {
+		reachedCheckpoints = append(reachedCheckpoints, p)
+	}
+
+	p.inside = detected
+}
+
+func (p Checkpoint) SpawnPos() rl.Vector2 {
+	return p.TopLeft()
+}
@@ -2,14 +2,29 @@ package models
 
 import (
 	"ahasuerus/collision"
+	"math"
+	"strings"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 type CollisionHitbox struct {
 	BaseEditorItem
-	CollisionProcessor collision.CollisionDetector `json:"-"`
-	hasCollision       bool                        `json:"-"`
+	// Shape is polygon outline relative to bounding rectangle (0..1 on each axis),
+	// empty shape is the rectangle itself
+	Shape []rl.Vector2 `json:",omitempty"`
+	// OneWay platform is passed from below and stands from above
+	OneWay bool `json:",omitempty"`
+	// Layer overrides default collision layer of object in game
+	Layer collision.Layer `json:",omitempty"`
+	// Material of surface, nil is default
+	Material *collision.Material `json:",omitempty"`
+	PlatformMotion
+
+	platform *platformState `json:"-"`
+
+	CollisionProcessor *collision.CollisionDetector `json:"-"`
+	hasCollision       bool                         `json:"-"`
 }
 
 func (p *CollisionHitbox) Load() {
@@ -27,22 +42,120 @@ func (p *CollisionHitbox) Draw() {
 	if DRAW_MODELS {
 		polys := p.PolygonsWithRotation()
 
+		color := rl.Blue
+		if p.OneWay {
+			color = rl.SkyBlue
+		}
+		if p.Layer&collision.LAYER_HAZARD != 0 {
+			color = rl.Red
+		}
+
 		for i, _ := range polys {
 			rl.DrawTriangleLines(
 				polys[i].Points[0],
 				polys[i].Points[1],
 				polys[i].Points[2],
-				rl.Blue,
+				color,
 			)
 		}
 
+		if p.Material != nil {
+			topLeft := p.TopLeft()
+			rl.DrawText(strings.ToUpper(p.Material.Name), int32(topLeft.X), int32(topLeft.Y), 30, color)
+		}
+
+		p.drawPath()
+
 		p.BaseEditorItem.Draw()
 	}
 
 }
 
 func (p *CollisionHitbox) Update(delta float32) {
+	if p.platform != nil {
+		p.updatePlatform(delta)
+	}
+}
 
+// ShapePoints returns outline in world coordinates without rotation
+func (p CollisionHitbox) ShapePoints() []rl.Vector2 {
+	topLeft := p.TopLeft()
+	width := p.Width()
+	height := p.Height()
+
+	if len(p.Shape) < 3 {
+		return []rl.Vector2{
+			topLeft,
+			rl.NewVector2(topLeft.X+width, topLeft.Y),
+			rl.NewVector2(topLeft.X+width, topLeft.Y+height),
+			rl.NewVector2(topLeft.X, topLeft.Y+height),
+		}
+	}
+
+	points := make([]rl.Vector2, len(p.Shape))
+	for i, _ := range p.Shape {
+		points[i] = rl.NewVector2(topLeft.X+p.Shape[i].X*width, topLeft.Y+p.Shape[i].Y*height)
+	}
+	return points
+}
+
+// SetShapePoints sets outline in world coordinates, bounding rectangle follows the outline
+func (p *CollisionHitbox) SetShapePoints(points []rl.Vector2) {
+	if len(points) == 0 {
+		return
+	}
+
+	min := points[0]
+	max := points[0]
+	for i, _ := range points {
+		min.X = float32(math.Min(float64(min.X), float64(points[i].X)))
+		min.Y = float32(math.Min(float64(min.Y), float64(points[i].Y)))
+		max.X = float32(math.Max(float64(max.X), float64(points[i].X)))
+		max.Y = float32(math.Max(float64(max.Y), float64(points[i].Y)))
+	}
+	width := max.X - min.X
+	height := max.Y - min.Y
+
+	p.Polygons = RectanglePolygons(min, width, height)
+	p.Shape = make([]rl.Vector2, len(points))
+	for i, _ := range points {
+		p.Shape[i] = rl.Vector2Subtract(points[i], min)
+		if width != 0 {
+			p.Shape[i].X /= width
+		}
+		if height != 0 {
+			p.Shape[i].Y /= height
+		}
+	}
+}
+
+// BakeRotation moves rotation into outline points, so vertices can be edited in world coordinates
+func (p *CollisionHitbox) BakeRotation() {
+	points := p.rotatedShapePoints()
+	p.Rotation = 0
+	p.SetShapePoints(points)
+}
+
+func (p CollisionHitbox) rotatedShapePoints() []rl.Vector2 {
+	points := p.ShapePoints()
+	if p.Rotation != 0 {
+		radians := float64(p.Rotation) * math.Pi / 180.0
+		sin := float32(math.Sin(radians))
+		cos := float32(math.Cos(radians))
+		topLeft := p.TopLeft()
+		for i, _ := range points {
+			points[i].X, points[i].Y = rotatePoint(&topLeft, &points[i], sin, cos)
+		}
+	}
+	return points
+}
+
+// PolygonsWithRotation triangulates shape outline, rectangle hitboxes keep editor polygons
+func (p CollisionHitbox) PolygonsWithRotation() []collision.Polygon {
+	if len(p.Shape) < 3 {
+		return p.BaseEditorItem.PolygonsWithRotation()
+	}
+	return collision.Triangulate(p.rotatedShapePoints())
 }
 
 func (p CollisionHitbox) getDynamicHitbox() collision.Hitbox {
@@ -53,3 +166,11 @@ func (p CollisionHitbox) getDynamicHitbox() collision.Hitbox {
 	hb := GetDynamicHitboxFromMap(GetDynamicHitboxMap(topLeft, width, height))
 	return hb
 }
+
+// CollisionLayer returns layer of object in game, fallback when it is not overridden
+func (p CollisionHitbox) CollisionLayer(fallback collision.Layer) collision.Layer {
+	if p.Layer == 0 {
+		return fallback
+	}
+	return p.Layer
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	DASH_KEY                 = rl.KeyLeftControl
+	DASH_AFTERIMAGE_INTERVAL = 0.03 // seconds between afterimages
+	DASH_AFTERIMAGE_LIFE     = 0.25 // seconds afterimage fades
+)
+
+// afterimage is fading copy of player animation frame left behind by dash
+type afterimage struct {
+	animation Animation
+	life      float32
+}
+
+func (p *Player) WithDash(enabled bool) *Player {
+	p.dashEnabled = enabled
+	return p
+}
+
+// processDash starts dash on DASH_KEY and replaces velocity while dash lasts. Dash on
+// ground is limited by cooldown only, dash in air also spends one of air charges
+func (p *Player) processDash(velocity rl.Vector2, delta float32) rl.Vector2 {
+	if p.dashCooldown > 0 {
+		p.dashCooldown -= delta
+	}
+	if p.grounded {
+		p.dashCharges = p.Physics.DashAirCharges
+	}
+
+	canDash := p.dashEnabled && !p.paused && p.Physics.DashTime > 0 &&
+		p.dashTimer <= 0 && p.dashCooldown <= 0 && (p.grounded || p.dashCharges > 0)
+
+	if canDash && rl.IsKeyPressed(DASH_KEY) {
+		if !p.grounded {
+			p.dashCharges--
+		}
+		p.dashTimer = p.Physics.DashTime
+		p.dashCooldown = p.Physics.DashCooldown
+		p.dashDirection = 1
+		if p.orientation == Left {
+			p.dashDirection = -1
+		}
+		p.jumping = false
+		p.afterimageTimer = 0
+	}
+
+	if p.dashTimer <= 0 {
+		return velocity
+	}
+
+	p.dashTimer -= delta
+	p.spawnAfterimage(delta)
+
+	if p.dashTimer <= 0 { // leave dash with walking speed
+		return rl.NewVector2(p.dashDirection*p.Physics.MoveSpeed, 0)
+	}
+
+	// dash ignores gravity, movement is swept in resolveCollission so it stops at walls
+	speed := p.Physics.DashDistance / p.Physics.DashTime * delta
+	return rl.NewVector2(p.dashDirection*speed, 0)
+}
+
+func (p *Player) spawnAfterimage(delta float32) {
+	p.afterimageTimer -= delta
+	if p.afterimageTimer > 0 {
+		return
+	}
+	p.afterimageTimer = DASH_AFTERIMAGE_INTERVAL
+	p.afterimages = append(p.afterimages, afterimage{
+		animation: *p.animator.Current(),
+		life:      DASH_AFTERIMAGE_LIFE,
+	})
+}
+
+func (p *Player) updateAfterimages(delta float32) {
+	alive := p.afterimages[:0]
+	for i, _ := range p.afterimages {
+		p.afterimages[i].life -= delta
+		if p.afterimages[i].life > 0 {
+			alive = append(alive, p.afterimages[i])
+		}
+	}
+	p.afterimages = alive
+}
+
+func (p Player) drawAfterimages() {
+	for i, _ := range p.afterimages {
+		alpha := p.afterimages[i].life / DASH_AFTERIMAGE_LIFE
+		p.afterimages[i].animation.DrawTinted(rl.Fade(rl.SkyBlue, alpha*0.6))
+	}
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"ahasuerus/collision"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	PLAYER_MAX_HEALTH      = 3
+	HAZARD_DAMAGE          = 1
+	HURT_INVULNERABLE_TIME = 1.0 // seconds without damage after hit
+	HURT_KNOCKBACK         = 8   // pixels per frame away from hazard
+	HURT_BLINK_FRAMES      = 4   // invulnerable player blinks every 4 frames
+)
+
+func (p *Player) WithKillPlane(y float32) *Player {
+	p.killPlaneY = y
+	return p
+}
+
+func (p Player) IsDead() bool {
+	return p.dead
+}
+
+func (p Player) GetHealth() int32 {
+	return p.Health
+}
+
+// checkHazards hurts player touching hazard hitboxes and kills player below kill plane
+func (p *Player) checkHazards(delta float32) {
+	if p.invulnerableTimer > 0 {
+		p.invulnerableTimer -= delta
+	}
+
+	if p.killPlaneY != 0 && p.Pos.Y > p.killPlaneY {
+		p.die()
+		return
+	}
+
+	if p.invulnerableTimer > 0 {
+		return
+	}
+
+	collider := GetDynamicHitboxFromMap(GetDynamicHitboxMap(p.Pos, p.width, p.height))
+	collider.Mask = collision.LAYER_HAZARD
+	detected, contacts := p.CollisionProcessor.Detect(collider)
+	if !detected {
+		return
+	}
+
+	p.Health -= HAZARD_DAMAGE
+	if p.Health <= 0 {
+		p.die()
+		return
+	}
+
+	p.invulnerableTimer = HURT_INVULNERABLE_TIME
+	p.velocity = rl.Vector2Add(rl.Vector2Scale(contacts[0].Normal, HURT_KNOCKBACK), rl.NewVector2(0, -HURT_KNOCKBACK/2))
+	p.dashTimer = 0
+}
+
+func (p *Player) die() {
+	p.Health = 0
+	p.dead = true
+	p.velocity = rl.Vector2{}
+	p.dashTimer = 0
+	p.wallSlide = false
+}
+
+// Respawn revives player at pos with full health. Rewind history is dropped,
+// it belongs to timeline before respawn and level state is not rewound with it
+func (p *Player) Respawn(pos rl.Vector2) {
+	p.Pos = pos
+	p.Health = PLAYER_MAX_HEALTH
+	p.dead = false
+	p.invulnerableTimer = 0
+	p.velocity = rl.Vector2{}
+	p.carrier = nil
+	p.animator.Reset()
+	p.updateCurrentHitbox()
+	p.resetRewind()
+}
+
+// isBlinkHidden hides invulnerable player on every other blink period
+func (p Player) isBlinkHidden() bool {
+	return p.invulnerableTimer > 0 && !p.dead && (p.blinkCounter/HURT_BLINK_FRAMES)%2 == 1
+}
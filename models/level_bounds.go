@@ -0,0 +1,59 @@
+package models
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	levelBoundsHandleSize = 80
+)
+
+// LevelBounds is editor only item for explicit camera bounds of level
+type LevelBounds struct {
+	BaseEditorItem
+}
+
+func NewLevelBounds(bounds rl.Rectangle) *LevelBounds {
+	return &LevelBounds{
+		BaseEditorItem: NewBaseEditorItem(RectanglePolygons(rl.NewVector2(bounds.X, bounds.Y), bounds.Width, bounds.Height)),
+	}
+}
+
+func (p *LevelBounds) Rectangle() rl.Rectangle {
+	topLeft := p.TopLeft()
+	return rl.NewRectangle(topLeft.X, topLeft.Y, p.Width(), p.Height())
+}
+
+// only top left handle is selectable, bounds cover whole level
+func (p *LevelBounds) EditorDetectSelection() EditorItemDetectSelectionResult {
+	mousePos := rl.GetMousePosition()
+	topLeft := p.TopLeft()
+	handle := rl.NewRectangle(topLeft.X, topLeft.Y, levelBoundsHandleSize, levelBoundsHandleSize)
+	collission := rl.CheckCollisionPointRec(mousePos, handle)
+	if collission {
+		rl.DrawRectangleLinesEx(handle, 4, rl.Purple)
+
+		if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+			p.EditSelected = true
+		}
+	}
+
+	return EditorItemDetectSelectionResult{
+		Selected:  p.EditSelected,
+		Collision: collission,
+	}
+}
+
+func (p *LevelBounds) Draw() {
+	if DRAW_MODELS {
+		topLeft := p.TopLeft()
+		rl.DrawRectangleLinesEx(p.Rectangle(), 6, rl.Green)
+		rl.DrawRectangle(int32(topLeft.X), int32(topLeft.Y), levelBoundsHandleSize, levelBoundsHandleSize, rl.Green)
+		rl.DrawText("LEVEL BOUNDS", int32(topLeft.X)+levelBoundsHandleSize+10, int32(topLeft.Y)+10, 40, rl.Green)
+	}
+	p.BaseEditorItem.Draw()
+}
+
+func (p *LevelBounds) Update(delta float32) {
+
+}
@@ -1,5 +1,7 @@
 package models
 
+import "ahasuerus/collision"
+
 type Scene interface {
 	Run() Scene
 	Unload()
@@ -42,6 +44,13 @@ type EditorItemDetectSelectionResult struct {
 type EditorItem interface {
 	EditorDetectSelection() EditorItemDetectSelectionResult
 	ProcessEditorSelection() EditorItemProcessSelectionResult
+	GetBaseEditorItem() *BaseEditorItem
+}
+
+// EditorItemState is geometry of editor item used to undo editor changes
+type EditorItemState struct {
+	Polygons [2]collision.Polygon
+	Rotation float32
 }
 
 type EditorSelectedItem struct {
@@ -51,6 +51,9 @@ func (p *MusicStream) Draw() {
 }
 
 func (p *MusicStream) Update(delta float32) {
+	if p.directAudioPanel == nil {
+		return
+	}
 
 	if !p.isDirectPlay && !p.isReversePlay {
 		p.reverseAudioPanel.Pause()
@@ -133,6 +136,9 @@ func (p *MusicStream) Load() {
 }
 
 func (p *MusicStream) Unload() {
+	if p.directAudioPanel == nil { // already unloaded
+		return
+	}
 
 	err := p.directAudioPanel.Close()
 	if err != nil {
@@ -144,13 +150,22 @@ func (p *MusicStream) Unload() {
 		panic(err)
 	}
 
+	p.directAudioPanel = nil
+	p.reverseAudioPanel = nil
+	p.currentAudioPanel = nil
 }
 
 func (p *MusicStream) Resume() {
+	if p.directAudioPanel == nil {
+		return
+	}
 	p.directAudioPanel.Unpause()
 }
 
 func (p *MusicStream) Pause() {
+	if p.directAudioPanel == nil {
+		return
+	}
 	p.directAudioPanel.Pause()
 	p.reverseAudioPanel.Pause()
 }
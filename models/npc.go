@@ -1,6 +1,7 @@
 package models
 
 import (
+	"ahasuerus/collision"
 	"ahasuerus/config"
 	"ahasuerus/resources"
 
@@ -113,7 +114,9 @@ func (p *Npc) Update(delta float32) {
 		p.rewindModeStarted = false
 	}
 
-	detectedCollision, _ := p.CollisionProcessor.Detect(p.getDynamicHitbox())
+	collider := p.getDynamicHitbox()
+	collider.Mask = collision.LAYER_PLAYER
+	detectedCollision, _ := p.CollisionProcessor.Detect(collider)
 
 	if !p.hasCollision && detectedCollision {
 		p.enterCollision()
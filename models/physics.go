@@ -0,0 +1,201 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+const PHYSICS_PROFILE_PATH = "resources/physics/default.json"
+
+// PhysicsProfile tunes player movement
+type PhysicsProfile struct {
+	FallGravity float32 // added to fall speed each second
+	JumpForce   float32 // initial jump speed, pixels per frame
+	MoveSpeed   float32 // pixels per frame
+
+	CoyoteTime     float32 // seconds to jump after walking off ledge
+	JumpBufferTime float32 // seconds Space press waits for landing
+	JumpCutScale   float32 // rising speed kept when Space is released early, 1 disables
+
+	WallSlideSpeed   float32 // max fall speed while sliding down wall, pixels per frame
+	WallJumpForce    float32 // initial rising speed of wall jump
+	WallJumpPush     float32 // horizontal speed away from wall
+	WallJumpLockTime float32 // seconds move input is ignored after wall jump
+
+	DashDistance   float32 // pixels
+	DashTime       float32 // seconds dash lasts
+	DashCooldown   float32 // seconds from dash start to next dash
+	DashAirCharges int32   // dashes in air before landing
+}
+
+// PhysicsOverride replaces fields present in level JSON, zero included
+type PhysicsOverride struct {
+	FallGravity *float32 `json:",omitempty"`
+	JumpForce   *float32 `json:",omitempty"`
+	MoveSpeed   *float32 `json:",omitempty"`
+
+	CoyoteTime     *float32 `json:",omitempty"`
+	JumpBufferTime *float32 `json:",omitempty"`
+	JumpCutScale   *float32 `json:",omitempty"`
+
+	WallSlideSpeed   *float32 `json:",omitempty"`
+	WallJumpForce    *float32 `json:",omitempty"`
+	WallJumpPush     *float32 `json:",omitempty"`
+	WallJumpLockTime *float32 `json:",omitempty"`
+
+	DashDistance   *float32 `json:",omitempty"`
+	DashTime       *float32 `json:",omitempty"`
+	DashCooldown   *float32 `json:",omitempty"`
+	DashAirCharges *int32   `json:",omitempty"`
+}
+
+var DEFAULT_PHYSICS = PhysicsProfile{
+	FallGravity: 20,
+	JumpForce:   10,
+	MoveSpeed:   5,
+
+	CoyoteTime:     0.1,
+	JumpBufferTime: 0.12,
+	JumpCutScale:   0.5,
+
+	WallSlideSpeed:   2,
+	WallJumpForce:    10,
+	WallJumpPush:     8,
+	WallJumpLockTime: 0.2,
+
+	DashDistance:   250,
+	DashTime:       0.15,
+	DashCooldown:   0.6,
+	DashAirCharges: 1,
+}
+
+// PhysicsField is tunable profile field, whole number fields are stored as int32
+type PhysicsField struct {
+	Label    string
+	Min, Max float32
+	Whole    bool
+
+	value    func(p *PhysicsProfile) float32
+	setValue func(p *PhysicsProfile, value float32)
+	override func(o *PhysicsOverride) (float32, bool)
+}
+
+func floatPhysicsField(label string, min, max float32, profile func(p *PhysicsProfile) *float32, override func(o *PhysicsOverride) *float32) PhysicsField {
+	return PhysicsField{
+		Label:    label,
+		Min:      min,
+		Max:      max,
+		value:    func(p *PhysicsProfile) float32 { return *profile(p) },
+		setValue: func(p *PhysicsProfile, value float32) { *profile(p) = value },
+		override: func(o *PhysicsOverride) (float32, bool) {
+			if value := override(o); value != nil {
+				return *value, true
+			}
+			return 0, false
+		},
+	}
+}
+
+func wholePhysicsField(label string, min, max float32, profile func(p *PhysicsProfile) *int32, override func(o *PhysicsOverride) *int32) PhysicsField {
+	return PhysicsField{
+		Label:    label,
+		Min:      min,
+		Max:      max,
+		Whole:    true,
+		value:    func(p *PhysicsProfile) float32 { return float32(*profile(p)) },
+		setValue: func(p *PhysicsProfile, value float32) { *profile(p) = int32(math.Round(float64(value))) },
+		override: func(o *PhysicsOverride) (float32, bool) {
+			if value := override(o); value != nil {
+				return float32(*value), true
+			}
+			return 0, false
+		},
+	}
+}
+
+// PHYSICS_FIELDS lists every profile field with its tuning range
+var PHYSICS_FIELDS = []PhysicsField{
+	floatPhysicsField("MOVE SPEED", 1, 20, func(p *PhysicsProfile) *float32 { return &p.MoveSpeed }, func(o *PhysicsOverride) *float32 { return o.MoveSpeed }),
+	floatPhysicsField("JUMP FORCE", 1, 30, func(p *PhysicsProfile) *float32 { return &p.JumpForce }, func(o *PhysicsOverride) *float32 { return o.JumpForce }),
+	floatPhysicsField("FALL GRAVITY", 1, 80, func(p *PhysicsProfile) *float32 { return &p.FallGravity }, func(o *PhysicsOverride) *float32 { return o.FallGravity }),
+	floatPhysicsField("COYOTE TIME", 0, 0.5, func(p *PhysicsProfile) *float32 { return &p.CoyoteTime }, func(o *PhysicsOverride) *float32 { return o.CoyoteTime }),
+	floatPhysicsField("JUMP BUFFER", 0, 0.5, func(p *PhysicsProfile) *float32 { return &p.JumpBufferTime }, func(o *PhysicsOverride) *float32 { return o.JumpBufferTime }),
+	floatPhysicsField("JUMP CUT", 0.1, 1, func(p *PhysicsProfile) *float32 { return &p.JumpCutScale }, func(o *PhysicsOverride) *float32 { return o.JumpCutScale }),
+	floatPhysicsField("WALL SLIDE", 0.5, 10, func(p *PhysicsProfile) *float32 { return &p.WallSlideSpeed }, func(o *PhysicsOverride) *float32 { return o.WallSlideSpeed }),
+	floatPhysicsField("WALL JUMP", 1, 30, func(p *PhysicsProfile) *float32 { return &p.WallJumpForce }, func(o *PhysicsOverride) *float32 { return o.WallJumpForce }),
+	floatPhysicsField("WALL PUSH", 1, 20, func(p *PhysicsProfile) *float32 { return &p.WallJumpPush }, func(o *PhysicsOverride) *float32 { return o.WallJumpPush }),
+	floatPhysicsField("WALL LOCK", 0, 1, func(p *PhysicsProfile) *float32 { return &p.WallJumpLockTime }, func(o *PhysicsOverride) *float32 { return o.WallJumpLockTime }),
+	floatPhysicsField("DASH DISTANCE", 50, 800, func(p *PhysicsProfile) *float32 { return &p.DashDistance }, func(o *PhysicsOverride) *float32 { return o.DashDistance }),
+	floatPhysicsField("DASH TIME", 0.05, 0.5, func(p *PhysicsProfile) *float32 { return &p.DashTime }, func(o *PhysicsOverride) *float32 { return o.DashTime }),
+	floatPhysicsField("DASH COOLDOWN", 0, 3, func(p *PhysicsProfile) *float32 { return &p.DashCooldown }, func(o *PhysicsOverride) *float32 { return o.DashCooldown }),
+	wholePhysicsField("DASH CHARGES", 0, 5, func(p *PhysicsProfile) *int32 { return &p.DashAirCharges }, func(o *PhysicsOverride) *int32 { return o.DashAirCharges }),
+}
+
+func (f PhysicsField) Value(p PhysicsProfile) float32 {
+	return f.value(&p)
+}
+
+// SetValue stores value, rounded for whole number fields
+func (f PhysicsField) SetValue(p *PhysicsProfile, value float32) {
+	f.setValue(p, value)
+}
+
+// Apply returns profile with fields present in override replaced
+func (o *PhysicsOverride) Apply(profile PhysicsProfile) PhysicsProfile {
+	if o == nil {
+		return profile
+	}
+	for _, field := range PHYSICS_FIELDS {
+		if value, ok := field.override(o); ok {
+			field.SetValue(&profile, value)
+		}
+	}
+	return profile
+}
+
+// Restore returns tuned profile with fields present in override taken back from base,
+// so values tuned in overridden level do not leak into base profile
+func (o *PhysicsOverride) Restore(tuned, base PhysicsProfile) PhysicsProfile {
+	if o == nil {
+		return tuned
+	}
+	for _, field := range PHYSICS_FIELDS {
+		if _, ok := field.override(o); ok {
+			field.SetValue(&tuned, field.Value(base))
+		}
+	}
+	return tuned
+}
+
+// LoadPhysicsProfile reads profile file on top of DEFAULT_PHYSICS, so file may omit fields
+func LoadPhysicsProfile(path string) (PhysicsProfile, error) {
+	profile := DEFAULT_PHYSICS
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return profile, err
+	}
+
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return DEFAULT_PHYSICS, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return profile, nil
+}
+
+func SavePhysicsProfile(path string, profile PhysicsProfile) error {
+	data, err := json.MarshalIndent(profile, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// BasePhysics returns default profile file, DEFAULT_PHYSICS when it can not be read
+func BasePhysics() PhysicsProfile {
+	profile, err := LoadPhysicsProfile(PHYSICS_PROFILE_PATH)
+	if err != nil {
+		fmt.Println("WARN: Physics profile not loaded, using defaults:", err)
+	}
+	return profile
+}
@@ -0,0 +1,233 @@
+package models
+
+import (
+	"ahasuerus/collision"
+	"math"
+
+	"github.com/fogleman/ease"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	DEFAULT_PATH_TIME = 2
+	DEFAULT_PATH_EASE = "inOutSine"
+)
+
+var (
+	PLATFORM_EASES = []string{"linear", "inOutSine", "inOutQuad", "inOutCubic", "outBounce", "outElastic"}
+
+	platformEaseFunctions = map[string]ease.Function{
+		"linear":     ease.Linear,
+		"inOutSine":  ease.InOutSine,
+		"inOutQuad":  ease.InOutQuad,
+		"inOutCubic": ease.InOutCubic,
+		"outBounce":  ease.OutBounce,
+		"outElastic": ease.OutElastic,
+	}
+)
+
+// PlatformMotion makes collision hitbox a kinematic platform, zero value is a static hitbox
+type PlatformMotion struct {
+	Path          []rl.Vector2 `json:",omitempty"` // waypoint offsets from authored top left
+	PathTime      float32      `json:",omitempty"` // seconds between waypoints
+	PathEase      string       `json:",omitempty"`
+	PathLoop      bool         `json:",omitempty"` // back to start after last waypoint, otherwise ping-pong
+	RotationSpeed float32      `json:",omitempty"` // degrees per second around center
+}
+
+func (m PlatformMotion) IsMoving() bool {
+	return len(m.Path) > 0 || m.RotationSpeed != 0
+}
+
+// Offset returns displacement from authored position at time in seconds
+func (m PlatformMotion) Offset(time float32) rl.Vector2 {
+	if len(m.Path) == 0 {
+		return rl.Vector2{}
+	}
+
+	points := append([]rl.Vector2{{}}, m.Path...)
+	segments := len(points) - 1
+	cycle := segments * 2
+	if m.PathLoop {
+		points = append(points, rl.Vector2{})
+		segments++
+		cycle = segments
+	}
+
+	pathTime := m.PathTime
+	if pathTime <= 0 {
+		pathTime = DEFAULT_PATH_TIME
+	}
+
+	step := float64(time / pathTime)
+	segment := int(math.Floor(step)) % cycle
+	if segment < 0 {
+		segment += cycle
+	}
+
+	from, to := segment, segment+1
+	if segment >= segments { // ping-pong way back
+		from, to = cycle-segment, cycle-segment-1
+	}
+
+	easeFunction, ok := platformEaseFunctions[m.PathEase]
+	if !ok {
+		easeFunction = platformEaseFunctions[DEFAULT_PATH_EASE]
+	}
+	t := float32(easeFunction(step - math.Floor(step)))
+
+	return rl.Vector2Lerp(points[from], points[to], t)
+}
+
+func (m PlatformMotion) Copy() PlatformMotion {
+	path := m.Path
+	if path != nil {
+		path = make([]rl.Vector2, len(m.Path))
+		copy(path, m.Path)
+	}
+	m.Path = path
+	return m
+}
+
+func (m PlatformMotion) Equals(other PlatformMotion) bool {
+	if len(m.Path) != len(other.Path) {
+		return false
+	}
+	for i, _ := range m.Path {
+		if m.Path[i] != other.Path[i] {
+			return false
+		}
+	}
+	return m.PathTime == other.PathTime &&
+		m.PathEase == other.PathEase &&
+		m.PathLoop == other.PathLoop &&
+		m.RotationSpeed == other.RotationSpeed
+}
+
+// platformState is game time of kinematic platform with its rewind buffer
+type platformState struct {
+	hitbox *collision.Hitbox
+
+	origin       rl.Vector2 // authored center
+	baseRotation float32
+	time         float32
+
+	Rewind               [REWIND_BUFFER_SIZE]float32
+	rewindLastIndex      int32
+	rewindSpeed          int32
+	rewindModeStartIndex int32
+	rewindModeStarted    bool
+}
+
+// StartMotion moves hitbox along its path and keeps registered hitbox up to date,
+// hitbox should be registered in detectors with AddDynamicHitbox
+func (p *CollisionHitbox) StartMotion(hitbox *collision.Hitbox) {
+	p.platform = &platformState{
+		hitbox:       hitbox,
+		origin:       p.rotatedCenter(),
+		baseRotation: p.Rotation,
+		rewindSpeed:  1,
+	}
+}
+
+func (p CollisionHitbox) rotatedCenter() rl.Vector2 {
+	halfSize := rl.NewVector2(p.Width()/2, p.Height()/2)
+	return rl.Vector2Add(p.TopLeft(), Vec2Rotate(halfSize, float64(p.Rotation)))
+}
+
+func (p *CollisionHitbox) updatePlatform(delta float32) {
+	state := p.platform
+
+	rewindEnabled := rl.IsKeyDown(rl.KeyLeftShift)
+	if rewindEnabled {
+		state.updateRewindSpeed()
+		state.rewindPlatform()
+		state.rewindModeStarted = true
+	} else {
+		state.time += delta
+		state.savePlatformToRewind()
+		state.rewindModeStarted = false
+	}
+
+	prevCenter := p.rotatedCenter()
+	prevRotation := p.Rotation
+
+	p.Rotation = state.baseRotation + p.RotationSpeed*state.time
+	center := rl.Vector2Add(state.origin, p.Offset(state.time))
+	halfSize := rl.NewVector2(p.Width()/2, p.Height()/2)
+	p.ChangePosition(rl.Vector2Subtract(center, Vec2Rotate(halfSize, float64(p.Rotation))))
+
+	state.hitbox.Update(p.PolygonsWithRotation())
+	state.hitbox.Rotation = p.Rotation
+	state.hitbox.Motion = rl.Vector2Subtract(center, prevCenter)
+	state.hitbox.Spin = p.Rotation - prevRotation
+	state.hitbox.Pivot = center
+}
+
+func (s *platformState) savePlatformToRewind() {
+	if int(s.rewindLastIndex) == len(s.Rewind)-1 {
+		s.rewindLastIndex = 0
+	}
+
+	s.Rewind[s.rewindLastIndex] = s.time
+	s.rewindLastIndex++
+}
+
+func (s *platformState) updateRewindSpeed() {
+	rewindEnabled := rl.IsKeyDown(rl.KeyLeftShift)
+	if rewindEnabled {
+		if rl.IsKeyReleased(rl.KeyDown) {
+			s.rewindSpeed--
+			if s.rewindSpeed < MIN_REWIND_SPEED {
+				s.rewindSpeed = MIN_REWIND_SPEED
+			}
+		}
+
+		if rl.IsKeyReleased(rl.KeyUp) {
+			s.rewindSpeed++
+			if s.rewindSpeed > MAX_REWIND_SPEED {
+				s.rewindSpeed = MAX_REWIND_SPEED
+			}
+		}
+	}
+}
+
+func (s *platformState) rewindPlatform() {
+	if !s.rewindModeStarted {
+		s.rewindModeStartIndex = s.rewindLastIndex
+		s.rewindSpeed = 1
+	}
+
+	rewind := s.Rewind[s.rewindLastIndex]
+
+	if s.rewindLastIndex > s.rewindSpeed && s.rewindLastIndex < s.rewindModeStartIndex+s.rewindSpeed {
+		rewind = s.Rewind[s.rewindLastIndex-s.rewindSpeed]
+		s.rewindLastIndex -= s.rewindSpeed
+	}
+
+	s.time = rewind
+}
+
+// drawPath shows waypoints of platform in editor
+func (p CollisionHitbox) drawPath() {
+	if len(p.Path) == 0 {
+		return
+	}
+
+	topLeft := p.TopLeft()
+	if p.platform != nil { // authored position
+		halfSize := rl.NewVector2(p.Width()/2, p.Height()/2)
+		topLeft = rl.Vector2Subtract(p.platform.origin, Vec2Rotate(halfSize, float64(p.platform.baseRotation)))
+	}
+
+	prev := topLeft
+	for i, _ := range p.Path {
+		waypoint := rl.Vector2Add(topLeft, p.Path[i])
+		rl.DrawLineEx(prev, waypoint, 3, rl.Green)
+		rl.DrawRectangleLines(int32(waypoint.X), int32(waypoint.Y), int32(p.Width()), int32(p.Height()), rl.Green)
+		prev = waypoint
+	}
+	if p.PathLoop {
+		rl.DrawLineEx(prev, topLeft, 3, rl.Green)
+	}
+}
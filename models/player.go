@@ -10,40 +10,73 @@ import (
 )
 
 const (
-	JUMP_SPEED        = 350
-	FALL_GRAVITY      = 20
-	PUSH_FORCE        = 5
-	JUMP_FORCE        = 10
-	PLAYER_MOVE_SPEED = 5
+	COLLISION_ITERATIONS = 4
+	COLLISION_SLOP       = 0.01
+	GROUND_NORMAL_Y      = 0.5 // slopes up to 60 degrees are ground
+
+	ONE_WAY_SLOPE_TOLERANCE = 2   // player walking up one-way slope rises up to 2px per 1px of move
+	WALL_NORMAL_X           = 0.9 // contacts steeper than ~25 degrees from vertical are walls
+
+	MIN_BOUNCE_SPEED  = 2   // slower landings do not bounce, player rests on bouncy surface
+	STICKY_JUMP_SCALE = 0.5 // jump from sticky surface is weaker
 
 	MIN_REWIND_SPEED = -4
 	MAX_REWIND_SPEED = 4
 )
 
 type PlayerRewindData struct {
-	Pos              rl.Vector2
-	orientation      Orientation
-	currentAnimation *Animation
-	velocity         rl.Vector2
+	Pos            rl.Vector2
+	orientation    Orientation
+	animationState string
+	velocity       rl.Vector2
+	wallSlide      bool
+	wallNormalX    float32
+	wallJumpTimer  float32
+	dashTimer      float32
+	dashCooldown   float32
+	dashCharges    int32
+	dashDirection  float32
+	health         int32
+	dead           bool
+	invulnerable   float32
 }
 
 type Player struct {
-	Pos                rl.Vector2                  `json:"-"`
-	CollisionProcessor collision.CollisionDetector `json:"-"`
-	velocity           rl.Vector2                  `json:"-"`
-	jumpCounter        uint8                       `json:"-"`
+	Pos                rl.Vector2                   `json:"-"`
+	CollisionProcessor *collision.CollisionDetector `json:"-"`
+	velocity           rl.Vector2                   `json:"-"`
+	coyoteTimer        float32                      `json:"-"` // time left to jump after leaving ground
+	jumpBufferTimer    float32                      `json:"-"` // time left for pressed jump to fire on landing
+	jumping            bool                         `json:"-"` // rising after jump, releasing Space cuts it
+	wallSlide          bool                         `json:"-"`
+	wallNormalX        float32                      `json:"-"` // direction away from touched wall, 0 without wall
+	wallJumpTimer      float32                      `json:"-"` // time left before move input controls player after wall jump
+	grounded           bool                         `json:"-"`
+	dropThrough        bool                         `json:"-"`
+	carrier            *collision.Hitbox            `json:"-"`
+	Physics            PhysicsProfile               `json:"-"`
+
+	dashEnabled     bool         `json:"-"`
+	dashTimer       float32      `json:"-"` // time left of current dash
+	dashCooldown    float32      `json:"-"`
+	dashCharges     int32        `json:"-"` // dashes left before landing
+	dashDirection   float32      `json:"-"`
+	afterimages     []afterimage `json:"-"`
+	afterimageTimer float32      `json:"-"`
+
+	Health            int32   `json:"-"`
+	dead              bool    `json:"-"`
+	invulnerableTimer float32 `json:"-"` // time left without hazard damage
+	blinkCounter      int32   `json:"-"`
+	killPlaneY        float32 `json:"-"` // player below this line dies, zero disables
 
 	width, height float32           `json:"-"`
 	orientation   Orientation       `json:"-"`
 	currentHitbox *collision.Hitbox `json:"-"`
 
-	currentAnimation    *Animation `json:"-"`
-	runAnimation        *Animation `json:"-"`
-	stayAnimation       *Animation `json:"-"`
-	directUpAnimation   *Animation `json:"-"`
-	directDownAnimation *Animation `json:"-"`
-	sideUpAnimation     *Animation `json:"-"`
-	sideDownAnimation   *Animation `json:"-"`
+	AnimationGraph  resources.AnimationGraph `json:"-"`
+	animator        *Animator                `json:"-"`
+	animationParams AnimationParams          `json:"-"` // reused every frame
 
 	Shader      rl.Shader            `json:"-"`
 	ImageShader resources.GameShader `json:"-"`
@@ -63,10 +96,14 @@ type Player struct {
 func NewPlayer(x float32, y float32) *Player {
 
 	p := &Player{
-		Pos:         rl.NewVector2(x, y),
-		rewindSpeed: 1,
+		Pos:            rl.NewVector2(x, y),
+		Physics:        DEFAULT_PHYSICS,
+		Health:         PLAYER_MAX_HEALTH,
+		AnimationGraph: resources.PlayerAnimationGraph,
+		rewindSpeed:    1,
 	}
 	hb := GetDynamicHitboxFromMap(GetDynamicHitboxMap(p.Pos, p.width, p.height))
+	hb.Layer = collision.LAYER_PLAYER
 	p.currentHitbox = &hb
 
 	return p
@@ -85,28 +122,18 @@ func (p *Player) IsCollisionRewind() bool {
 }
 
 func (p *Player) Load() {
-	p.runAnimation = NewAnimation(resources.PlayerRunTexture, 27, Loop).FramesPerSecond(30)
-	p.runAnimation.Load()
-
-	p.stayAnimation = NewAnimation(resources.PlayerStayTexture, 22, Loop).FramesPerSecond(7)
-	p.stayAnimation.Load()
-
-	p.directUpAnimation = NewAnimation(resources.PlayerDirectUpTexture, 5, Temporary).TimeInSeconds(1)
-	p.directUpAnimation.Load()
-
-	p.directDownAnimation = NewAnimation(resources.PlayerDirectDownTexture, 6, Temporary).TimeInSeconds(1.5)
-	p.directDownAnimation.Load()
-
-	p.sideUpAnimation = NewAnimation(resources.PlayerSideUpTexture, 12, Temporary).TimeInSeconds(1)
-	p.sideUpAnimation.Load()
-
-	p.sideDownAnimation = NewAnimation(resources.PlayerSideDownTexture, 12, Temporary).TimeInSeconds(1.5)
-	p.sideDownAnimation.Load()
-
-	p.currentAnimation = p.stayAnimation
+	graph, err := LoadAnimationGraph(p.AnimationGraph)
+	if err != nil {
+		panic(err)
+	}
+	p.animator, err = NewAnimator(graph)
+	if err != nil {
+		panic(err)
+	}
+	p.animator.Load()
 
-	p.width = float32(p.stayAnimation.StepInPixel)
-	p.height = float32(p.stayAnimation.Texture.Height)
+	p.width = float32(p.animator.Current().StepInPixel)
+	p.height = float32(p.animator.Current().Texture.Height)
 
 	if p.ImageShader != resources.UndefinedShader {
 		p.Shader = resources.LoadShader(p.ImageShader)
@@ -126,8 +153,7 @@ func (p *Player) Load() {
 }
 
 func (p *Player) Unload() {
-	p.runAnimation.Unload()
-	p.stayAnimation.Unload()
+	p.animator.Unload()
 	if p.ImageShader != resources.UndefinedShader {
 		resources.UnloadShader(p.Shader)
 	}
@@ -143,12 +169,18 @@ func (p *Player) Pause() {
 
 func (p Player) Draw() {
 
-	if p.ImageShader != resources.UndefinedShader {
-		rl.BeginShaderMode(p.Shader)
-		p.currentAnimation.Draw()
-		rl.EndShaderMode()
-	} else {
-		p.currentAnimation.Draw()
+	p.drawAfterimages()
+
+	if p.dead {
+		p.animator.Current().DrawTinted(rl.Red)
+	} else if !p.isBlinkHidden() { // hurt player blinks
+		if p.ImageShader != resources.UndefinedShader {
+			rl.BeginShaderMode(p.Shader)
+			p.animator.Draw()
+			rl.EndShaderMode()
+		} else {
+			p.animator.Draw()
+		}
 	}
 
 	if DRAW_MODELS {
@@ -161,49 +193,62 @@ func (p Player) Draw() {
 func (p *Player) Update(delta float32) {
 
 	rewindEnabled := rl.IsKeyDown(rl.KeyLeftShift)
+	p.blinkCounter++
+
+	if !rewindEnabled && p.dead { // wait for rewind or respawn, history keeps in step with world
+		p.resolveAndUpdateAnimation(false, rl.Vector2{}, delta)
+		p.savePlayerToRewind()
+		p.rewindModeStarted = false
+		p.rewindCollision = false
+	} else if !rewindEnabled {
+		if p.carrier != nil { // ride moving platform
+			foot := rl.NewVector2(p.Pos.X+p.width/2, p.Pos.Y+p.height)
+			p.Pos = rl.Vector2Add(p.Pos, p.carrier.Carry(foot))
+			p.carrier = nil
+		}
 
-	if !rewindEnabled {
 		newVelocity := p.velocity
 
 		newVelocity = p.movementResist(newVelocity, 1, delta)
-		newVelocity.Y += FALL_GRAVITY * delta
+		newVelocity.Y += p.Physics.FallGravity * delta
 
-		newVelocity = p.processMoveXInput(newVelocity)
+		newVelocity = p.processMoveXInput(newVelocity, delta)
+		newVelocity = p.processJumpInput(newVelocity, delta)
+		newVelocity = p.processDash(newVelocity, delta)
 
-		futureHitboxMap := GetDynamicHitboxMap(rl.Vector2Add(p.Pos, newVelocity), p.width, p.height)
-		hitbox := GetDynamicHitboxFromMap(futureHitboxMap)
-		hasCollision, collisionMapArray := p.CollisionProcessor.Detect(hitbox)
-		if hasCollision {
-			for i, _ := range collisionMapArray {
-				newVelocity = p.resolveCollission(newVelocity, collisionMapArray[i], delta)
-			}
-		}
+		futurePos, newVelocity, hasCollision := p.resolveCollission(newVelocity, delta)
 
 		p.velocity = newVelocity
-		futurePos := rl.Vector2Add(p.Pos, p.velocity)
 
 		posDelta := rl.Vector2Subtract(p.Pos, futurePos)
 
 		p.Pos = futurePos
 
+		p.checkHazards(delta)
 		p.resolveAndUpdateAnimation(hasCollision, posDelta, delta)
 
 		p.savePlayerToRewind()
 		p.rewindModeStarted = false
 		p.rewindCollision = false
 	} else {
+		p.carrier = nil
+		p.coyoteTimer = 0
+		p.jumpBufferTimer = 0
+		p.jumping = false
 		p.updateRewindSpeed()
 		p.rewindPlayer()
 		if p.rewindSpeed > 0 {
-			p.currentAnimation.Reverse(true)
+			p.animator.Current().Reverse(true)
 			p.updateAnimation(delta, uint8(p.rewindSpeed))
-			p.currentAnimation.Reverse(false)
+			p.animator.Current().Reverse(false)
 		} else if p.rewindSpeed < 0 {
 			p.updateAnimation(delta, uint8(math.Abs(float64(p.rewindSpeed))))
 		}
 		p.rewindModeStarted = true
 	}
 
+	p.updateAfterimages(delta)
+
 	// update hitbox for others
 	p.updateCurrentHitbox()
 
@@ -219,14 +264,14 @@ func (p *Player) Update(delta float32) {
 
 		playerHitboxMap := GetDynamicHitboxMap(p.Pos, p.width, p.height)
 
-		rl.SetShaderValueTexture(p.Shader, p.shaderLocs[0], p.currentAnimation.Texture)
+		rl.SetShaderValueTexture(p.Shader, p.shaderLocs[0], p.animator.Current().Texture)
 		rl.SetShaderValue(p.Shader, p.shaderLocs[1], []float32{playerHitboxMap.center.X, playerHitboxMap.center.Y}, rl.ShaderUniformVec2)
 		rl.SetShaderValue(p.Shader, p.shaderLocs[2], []float32{float32(len(p.Lightboxes))}, rl.ShaderUniformFloat)
 
 		rl.SetShaderValueV(p.Shader, p.shaderLocs[3], lightPoints, rl.ShaderUniformVec2, int32(len(p.Lightboxes)))
 		rl.SetShaderValueV(p.Shader, p.shaderLocs[4], lightPointsRadius, rl.ShaderUniformFloat, int32(len(p.Lightboxes)))
-		rl.SetShaderValue(p.Shader, p.shaderLocs[5], []float32{float32(p.currentAnimation.Texture.Width)}, rl.ShaderUniformFloat)
-		rl.SetShaderValue(p.Shader, p.shaderLocs[6], []float32{float32(p.currentAnimation.Texture.Height)}, rl.ShaderUniformFloat)
+		rl.SetShaderValue(p.Shader, p.shaderLocs[5], []float32{float32(p.animator.Current().Texture.Width)}, rl.ShaderUniformFloat)
+		rl.SetShaderValue(p.Shader, p.shaderLocs[6], []float32{float32(p.animator.Current().Texture.Height)}, rl.ShaderUniformFloat)
 		rewind := 0.0
 		if p.rewindModeStarted {
 			rewind = 1.0
@@ -234,9 +279,6 @@ func (p *Player) Update(delta float32) {
 		rl.SetShaderValue(p.Shader, p.shaderLocs[7], []float32{float32(rewind)}, rl.ShaderUniformFloat)
 	}
 
-	if p.jumpCounter > 0 {
-		p.jumpCounter--
-	}
 }
 
 func (p *Player) savePlayerToRewind() {
@@ -245,14 +287,35 @@ func (p *Player) savePlayerToRewind() {
 	}
 
 	p.Rewind[p.rewindLastIndex] = PlayerRewindData{
-		Pos:              p.Pos,
-		orientation:      p.orientation,
-		currentAnimation: p.currentAnimation,
-		velocity:         p.velocity,
+		Pos:            p.Pos,
+		orientation:    p.orientation,
+		animationState: p.animator.State(),
+		velocity:       p.velocity,
+		wallSlide:      p.wallSlide,
+		wallNormalX:    p.wallNormalX,
+		wallJumpTimer:  p.wallJumpTimer,
+		dashTimer:      p.dashTimer,
+		dashCooldown:   p.dashCooldown,
+		dashCharges:    p.dashCharges,
+		dashDirection:  p.dashDirection,
+		health:         p.Health,
+		dead:           p.dead,
+		invulnerable:   p.invulnerableTimer,
 	}
 	p.rewindLastIndex++
 }
 
+// resetRewind forgets rewind history, every record holds current state so rewind can not go before this moment
+func (p *Player) resetRewind() {
+	p.rewindLastIndex = 0
+	p.savePlayerToRewind()
+	for i, _ := range p.Rewind {
+		p.Rewind[i] = p.Rewind[0]
+	}
+	p.rewindModeStarted = false
+	p.rewindCollision = false
+}
+
 func (p *Player) updateRewindSpeed() {
 	rewindEnabled := rl.IsKeyDown(rl.KeyLeftShift)
 	if rewindEnabled {
@@ -290,8 +353,18 @@ func (p *Player) rewindPlayer() {
 
 	p.Pos = rewind.Pos
 	p.orientation = rewind.orientation
-	p.currentAnimation = rewind.currentAnimation
+	p.animator.Restore(rewind.animationState)
 	p.velocity = rewind.velocity
+	p.wallSlide = rewind.wallSlide
+	p.wallNormalX = rewind.wallNormalX
+	p.wallJumpTimer = rewind.wallJumpTimer
+	p.dashTimer = rewind.dashTimer
+	p.dashCooldown = rewind.dashCooldown
+	p.dashCharges = rewind.dashCharges
+	p.dashDirection = rewind.dashDirection
+	p.Health = rewind.health
+	p.dead = rewind.dead
+	p.invulnerableTimer = rewind.invulnerable
 }
 
 func (p *Player) drawRewindSpeed() {
@@ -301,54 +374,50 @@ func (p *Player) drawRewindSpeed() {
 	}
 }
 
+// resolveAndUpdateAnimation feeds player state to animation graph, see resources/animations
 func (p *Player) resolveAndUpdateAnimation(hasCollision bool, posDelta rl.Vector2, delta float32) {
-
-	prevAnimation := p.currentAnimation
-
-	if posDelta.X != 0 && hasCollision {
-		p.currentAnimation = p.runAnimation
-	}
-
-	if posDelta.X == 0 && posDelta.Y == 0 {
-		p.currentAnimation = p.stayAnimation
-	}
-
-	if posDelta.Y > 1 {
-		p.currentAnimation = p.directUpAnimation
-		if posDelta.X != 0 {
-			p.currentAnimation = p.sideUpAnimation
-		}
-	}
-
-	if posDelta.Y < -2 {
-		p.currentAnimation = p.directDownAnimation
-		if posDelta.X != 0 {
-			p.currentAnimation = p.sideDownAnimation
-		}
-	}
-
-	if p.currentAnimation != prevAnimation {
-		p.currentAnimation.Begin()
-	}
+	if p.animationParams == nil {
+		p.animationParams = make(AnimationParams)
+	}
+	params := p.animationParams
+	params["collision"] = boolParam(hasCollision)
+	params["movingX"] = boolParam(posDelta.X != 0)
+	params["movingY"] = boolParam(posDelta.Y != 0)
+	params["rise"] = posDelta.Y
+	params["grounded"] = boolParam(p.grounded)
+	params["wallSlide"] = boolParam(p.wallSlide)
+	params["wallJump"] = boolParam(p.wallJumpTimer > 0)
+	params["dashing"] = boolParam(p.dashTimer > 0)
+	params["hurt"] = boolParam(p.invulnerableTimer > 0)
+	params["dead"] = boolParam(p.dead)
+	p.animator.Evaluate(params)
 	p.updateAnimation(delta, 1)
 }
 
 func (p *Player) updateAnimation(delta float32, speed uint8) {
-	p.currentAnimation.Pos.X = p.Pos.X
-	p.currentAnimation.Pos.Y = p.Pos.Y
-	p.currentAnimation.Orientation = p.orientation
-	p.currentAnimation.AnimationSpeed(speed)
-	p.currentAnimation.Update(delta)
+	p.animator.Update(delta, p.Pos, p.orientation, speed)
 }
 
-func (p *Player) processMoveXInput(velocity rl.Vector2) rl.Vector2 {
+func boolParam(b bool) float32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (p *Player) processMoveXInput(velocity rl.Vector2, delta float32) rl.Vector2 {
+	if p.wallJumpTimer > 0 { // wall jump push is not cancelled by holding toward wall
+		p.wallJumpTimer -= delta
+		return velocity
+	}
+
 	if rl.IsKeyDown(rl.KeyLeft) && !p.paused {
-		velocity.X = (-1) * PLAYER_MOVE_SPEED
+		velocity.X = (-1) * p.Physics.MoveSpeed
 		p.orientation = Left
 	}
 
 	if rl.IsKeyDown(rl.KeyRight) && !p.paused {
-		velocity.X = PLAYER_MOVE_SPEED
+		velocity.X = p.Physics.MoveSpeed
 		p.orientation = Right
 	}
 	return velocity
@@ -359,104 +428,237 @@ func (p *Player) AddLightbox(lp Light) *Player {
 	return p
 }
 
+func (p *Player) WithPhysics(profile PhysicsProfile) *Player {
+	p.Physics = profile
+	return p
+}
+
 func (p *Player) WithShader(gs resources.GameShader) *Player {
 	p.ImageShader = gs
 	return p
 }
 
-func (p *Player) resolveCollission(velocity rl.Vector2, collisionMap map[int]float32, delta float32) rl.Vector2 {
-	_, topLeft := collisionMap[0]
-	_, topRight := collisionMap[1]
+// processJumpInput buffers Space press and cuts jump short when Space is released while rising
+func (p *Player) processJumpInput(velocity rl.Vector2, delta float32) rl.Vector2 {
+	if rl.IsKeyPressed(rl.KeySpace) && !p.paused {
+		p.jumpBufferTimer = p.Physics.JumpBufferTime
+	} else if p.jumpBufferTimer > 0 {
+		p.jumpBufferTimer -= delta
+	}
 
-	_, rightTop := collisionMap[2]
-	_, rightBottom := collisionMap[3]
+	if p.jumping && velocity.Y >= 0 {
+		p.jumping = false
+	}
+	if p.jumping && !rl.IsKeyDown(rl.KeySpace) {
+		velocity.Y *= p.Physics.JumpCutScale
+		p.jumping = false
+	}
 
-	bottomRightRotation, bottomRight := collisionMap[4]
-	bottomLeftRotation, bottomLeft := collisionMap[5]
+	return velocity
+}
 
-	_, leftBottom := collisionMap[6]
-	_, leftTop := collisionMap[7]
+// resolveCollission moves player by velocity and pushes it out of hitboxes
+// along contact normals, velocity loses its part directed into surfaces
+func (p *Player) resolveCollission(velocity rl.Vector2, delta float32) (rl.Vector2, rl.Vector2, bool) {
+	pos := p.sweep(velocity)
+
+	hasCollision := false
+	grounded := false
+	solidGround := false
+	groundNormal := rl.NewVector2(0, -1)
+
+	groundMaterial := collision.DEFAULT_MATERIAL
+	bounced := false
+	wallNormalX := float32(0)
+
+	applyContact := func(contact collision.Contact) {
+		normal := contact.Normal
+		material := contact.Hitbox.SurfaceMaterial()
+		isGround := normal.Y <= -GROUND_NORMAL_Y
+		if isGround {
+			grounded = true
+			groundNormal = normal
+			groundMaterial = material
+			solidGround = solidGround || !contact.Hitbox.OneWay
+			p.carrier = contact.Hitbox
+		}
+		if float32(math.Abs(float64(normal.X))) >= WALL_NORMAL_X {
+			wallNormalX = normal.X
+		}
 
-	if bottomRight && bottomLeft { // fall on ground
-		velocity.Y = 0
-		velocity = p.movementResist(velocity, 7, delta)
-		velocity = Vec2Rotate(velocity, math.Max(float64(bottomLeftRotation), float64(bottomRightRotation)))
+		intoSurface := rl.Vector2DotProduct(velocity, normal)
+		if intoSurface < 0 {
+			restitution := material.Restitution
+			if material.Sticky || -intoSurface < MIN_BOUNCE_SPEED {
+				restitution = 0
+			}
+			velocity = rl.Vector2Subtract(velocity, rl.Vector2Scale(normal, intoSurface*(1+restitution)))
+			bounced = bounced || (isGround && restitution > 0)
+		}
 	}
 
-	if leftTop || leftBottom { // left wall collision
-		velocity.X = 0
+	oneWayOverlap := false
+
+	for i := 0; i < COLLISION_ITERATIONS; i++ {
+		collider := GetDynamicHitboxFromMap(GetDynamicHitboxMap(pos, p.width, p.height))
+		collider.Mask = collision.LAYER_WORLD
+		detected, contacts := p.CollisionProcessor.Detect(collider)
+		if !detected {
+			break
+		}
+
+		contacts, overlap := p.solidContacts(contacts, pos, velocity)
+		oneWayOverlap = oneWayOverlap || overlap
+		if len(contacts) == 0 {
+			break
+		}
+		hasCollision = true
+
+		deepest := contacts[0]
+		for j, _ := range contacts {
+			if contacts[j].Depth > deepest.Depth {
+				deepest = contacts[j]
+			}
+		}
+
+		if deepest.Depth <= COLLISION_SLOP { // only touching
+			for j, _ := range contacts {
+				applyContact(contacts[j])
+			}
+			break
+		}
+
+		pos = rl.Vector2Add(pos, rl.Vector2Scale(deepest.Normal, deepest.Depth))
+		applyContact(deepest)
 	}
 
-	if rightTop || rightBottom { // right wall collision
-		velocity.X = 0
+	if !oneWayOverlap {
+		p.dropThrough = false
 	}
 
-	bottomLeftStuck := bottomLeft && leftBottom && !bottomRight && !leftTop // slide down-side when on border of collision box
-	if bottomLeftStuck {
-		velocity.X = 3
-		velocity.Y = 3
+	if grounded && !bounced {
+		velocity = p.movementResist(velocity, groundMaterial.Friction, delta)
+
+		// move along ground surface with horizontal speed, gravity does not slide player down slopes
+		tangent := rl.NewVector2(-groundNormal.Y, groundNormal.X)
+		velocity = rl.Vector2Scale(tangent, velocity.X/tangent.X)
+
+		// conveyor carries player without changing its own velocity
+		pos = rl.Vector2Add(pos, rl.Vector2Scale(tangent, groundMaterial.ConveyorSpeed*delta))
+
+		p.coyoteTimer = p.Physics.CoyoteTime
+	} else if p.coyoteTimer > 0 {
+		p.coyoteTimer -= delta
 	}
-	bottomRightStuck := bottomRight && rightBottom && !bottomLeft && !rightTop // slide down-side when on border of collision box
-	if bottomRightStuck {
-		velocity.X = -3
-		velocity.Y = 3
+
+	p.grounded = grounded && !bounced
+	p.wallNormalX = wallNormalX
+	p.updateWallSlide(grounded)
+	if p.wallSlide && velocity.Y > p.Physics.WallSlideSpeed {
+		velocity.Y = p.Physics.WallSlideSpeed
 	}
 
-	if topLeft || topRight { // top collission
-		if velocity.Y < 0 {
-			velocity.Y = 0
+	if p.jumpBufferTimer > 0 {
+		dropDown := grounded && rl.IsKeyDown(rl.KeyDown) && !solidGround
+
+		if dropDown { // fall through one-way platform
+			p.dropThrough = true
+			p.jumpBufferTimer = 0
+		} else if (grounded && !bounced) || p.coyoteTimer > 0 { // jump, buffered press fires on landing
+			velocity.Y = (-1) * (p.Physics.JumpForce)
+			if grounded && groundMaterial.Sticky {
+				velocity.Y *= STICKY_JUMP_SCALE
+			}
+			p.jumpBufferTimer = 0
+			p.coyoteTimer = 0
+			p.jumping = true
+		} else if p.wallNormalX != 0 { // wall jump pushes away from wall
+			velocity = rl.NewVector2(p.wallNormalX*p.Physics.WallJumpPush, (-1)*p.Physics.WallJumpForce)
+			p.orientation = Right
+			if p.wallNormalX < 0 {
+				p.orientation = Left
+			}
+			p.jumpBufferTimer = 0
+			p.jumping = true
+			p.wallSlide = false
+			p.wallJumpTimer = p.Physics.WallJumpLockTime
 		}
 	}
 
-	topLeftStuck := topLeft && leftTop && !leftBottom && !topRight // topLeft border stuck SLIDE DOWN
-	if topLeftStuck {
-		velocity.X = 3
-		velocity.Y = 3
-	}
+	return pos, velocity, hasCollision
+}
 
-	topRightStuck := topRight && rightTop && !rightBottom && !topLeft // top right border stuck SLIDE DOWN
-	if topRightStuck {
-		velocity.X = -3
-		velocity.Y = 3
-	}
+// updateWallSlide starts slide when airborne player holds toward touched wall
+func (p *Player) updateWallSlide(grounded bool) {
+	holdsToWall := (p.wallNormalX > 0 && rl.IsKeyDown(rl.KeyLeft)) || (p.wallNormalX < 0 && rl.IsKeyDown(rl.KeyRight))
+	p.wallSlide = !grounded && p.wallNormalX != 0 && holdsToWall && !p.paused
+}
 
-	if !leftBottom && leftTop && !topLeft { // LEFT SINGLE TOP COLLISION
-		if velocity.Y < 0 {
-			velocity.Y = 0
+// sweep moves player along velocity up to earliest impact with solid hitbox, so fast
+// motion does not tunnel through thin platforms. Part of motion left after impact
+// slides along surface, overlaps at start are left to pushout in resolveCollission
+func (p *Player) sweep(velocity rl.Vector2) rl.Vector2 {
+	collider := GetDynamicHitboxFromMap(GetDynamicHitboxMap(p.Pos, p.width, p.height))
+	collider.Mask = collision.LAYER_WORLD
+
+	hits := p.CollisionProcessor.CastAll(collider, velocity)
+	for i, _ := range hits {
+		hit := hits[i]
+		if hit.Fraction <= 0 {
+			continue
+		}
+		if hit.Hitbox.OneWay && (p.dropThrough || hit.Normal.Y > -GROUND_NORMAL_Y) {
+			continue
 		}
-	}
 
-	if !rightBottom && rightTop && !topRight { // RIGHT SINGLE TOP COLLISION
-		if velocity.Y < 0 {
-			velocity.Y = 0
+		remaining := rl.Vector2Scale(velocity, 1-hit.Fraction)
+		intoSurface := rl.Vector2DotProduct(remaining, hit.Normal)
+		if intoSurface < 0 {
+			remaining = rl.Vector2Subtract(remaining, rl.Vector2Scale(hit.Normal, intoSurface))
 		}
+		return rl.Vector2Add(rl.Vector2Add(p.Pos, hit.Offset), remaining)
 	}
 
-	if bottomRight && bottomLeft {
-		p.jumpCounter = 0
-	}
+	return rl.Vector2Add(p.Pos, velocity)
+}
+
+// solidContacts drops contacts with one-way platforms which player passes from below,
+// from side or drops through. Second result reports overlap with any one-way platform
+func (p *Player) solidContacts(contacts []collision.Contact, pos rl.Vector2, velocity rl.Vector2) ([]collision.Contact, bool) {
+	solid := make([]collision.Contact, 0, len(contacts))
+	oneWayOverlap := false
 
-	// jump
-	if bottomRight || bottomLeft && (!leftBottom && !rightBottom && !leftTop && !rightTop) {
-		spacePressed := rl.IsKeyDown(rl.KeySpace)
-		if spacePressed && p.jumpCounter == 0 {
-			velocity.Y = (-1) * (JUMP_FORCE)
-			p.jumpCounter = uint8(FPS)
+	for i, _ := range contacts {
+		contact := contacts[i]
+		if contact.Hitbox.OneWay {
+			oneWayOverlap = true
+
+			if p.dropThrough || contact.Normal.Y > -GROUND_NORMAL_Y {
+				continue
+			}
+
+			// landing must not lift player above previous position, otherwise player comes from below
+			resolvedY := pos.Y + contact.Normal.Y*contact.Depth
+			tolerance := float32(math.Abs(float64(velocity.X)))*ONE_WAY_SLOPE_TOLERANCE + COLLISION_SLOP
+			if resolvedY < p.Pos.Y-tolerance {
+				continue
+			}
 		}
+		solid = append(solid, contact)
 	}
 
-	return velocity
+	return solid, oneWayOverlap
 }
 
 func (p *Player) movementResist(velocity rl.Vector2, resistScale float32, delta float32) rl.Vector2 {
 	if velocity.X > 0 {
-		velocity.X += -1 * PLAYER_MOVE_SPEED * resistScale * delta
+		velocity.X += -1 * p.Physics.MoveSpeed * resistScale * delta
 		if velocity.X < 0 {
 			velocity.X = 0
 		}
 	}
 	if velocity.X < 0 {
-		velocity.X += PLAYER_MOVE_SPEED * resistScale * delta
+		velocity.X += p.Physics.MoveSpeed * resistScale * delta
 		if velocity.X > 0 {
 			velocity.X = 0
 		}
@@ -479,5 +681,5 @@ func (p Player) GetHitbox() *collision.Hitbox {
 
 func (p *Player) updateCurrentHitbox() {
 	updatedHb := GetDynamicHitboxFromMap(GetDynamicHitboxMap(p.Pos, p.width, p.height))
-	p.currentHitbox.Polygons = updatedHb.Polygons
+	p.currentHitbox.Update(updatedHb.Polygons)
 }
@@ -0,0 +1,51 @@
+package models
+
+var (
+	collectedItems = make(map[string]bool)
+)
+
+func CollectItem(id string) {
+	collectedItems[id] = true
+}
+
+func GetCollectedItems() []string {
+	items := make([]string, 0, len(collectedItems))
+	for id, _ := range collectedItems {
+		items = append(items, id)
+	}
+	return items
+}
+
+func ResetCollectedItems(items []string) {
+	collectedItems = make(map[string]bool)
+	for i, _ := range items {
+		collectedItems[items[i]] = true
+	}
+}
+
+var (
+	flags = make(map[string]bool)
+)
+
+func SetFlag(name string) {
+	flags[name] = true
+}
+
+func IsFlagSet(name string) bool {
+	return flags[name]
+}
+
+func GetFlags() []string {
+	result := make([]string, 0, len(flags))
+	for name, _ := range flags {
+		result = append(result, name)
+	}
+	return result
+}
+
+func ResetFlags(names []string) {
+	flags = make(map[string]bool)
+	for i, _ := range names {
+		flags[names[i]] = true
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"math"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
 	"github.com/google/uuid"
 )
@@ -57,6 +59,13 @@ func (p *Text) WithExpire(liveSeconds int, expireCallback func(text *Text)) *Tex
 	return p
 }
 
+// WithExpireSeconds is WithExpire for fractional seconds, rounded up to whole frames
+func (p *Text) WithExpireSeconds(liveSeconds float32, expireCallback func(text *Text)) *Text {
+	p.WithExpire(0, expireCallback)
+	p.expireFrames = int(math.Ceil(float64(liveSeconds * float32(FPS))))
+	return p
+}
+
 func (p *Text) Update(delta float32) {
 	p.updateCallback(p)
 	if p.expireMode {
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"ahasuerus/collision"
+	"fmt"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type TriggerEvent string
+
+const (
+	TriggerEnter TriggerEvent = "enter"
+	TriggerExit  TriggerEvent = "exit"
+	TriggerStay  TriggerEvent = "stay"
+)
+
+type TriggerActionType string
+
+const (
+	ChangeLevelAction    TriggerActionType = "changeLevel"
+	StartMusicAction     TriggerActionType = "startMusic"
+	SpawnParticlesAction TriggerActionType = "spawnParticles"
+	ShowTextAction       TriggerActionType = "showText"
+	SetFlagAction        TriggerActionType = "setFlag"
+	CollectItemAction    TriggerActionType = "collectItem"
+	MoveCameraAction     TriggerActionType = "moveCamera"
+)
+
+var (
+	TRIGGER_EVENTS       = []TriggerEvent{TriggerEnter, TriggerExit, TriggerStay}
+	TRIGGER_ACTION_TYPES = []TriggerActionType{ChangeLevelAction, StartMusicAction, SpawnParticlesAction, ShowTextAction, SetFlagAction, CollectItemAction, MoveCameraAction}
+
+	firedTriggerActions = make([]FiredTriggerAction, 0)
+)
+
+type TriggerAction struct {
+	Event    TriggerEvent
+	Type     TriggerActionType
+	Value    string     // level id, music path, particle type, text, flag name or item id
+	Reverse  string     `json:",omitempty"` // reverse music path
+	Offset   rl.Vector2 // particles position or camera target relative to trigger center
+	Duration float32    // seconds to show text or hold camera
+}
+
+// IsAllowed rejects actions which would pile up objects when fired every frame
+func (a TriggerAction) IsAllowed() bool {
+	return !(a.Event == TriggerStay && a.Type == SpawnParticlesAction)
+}
+
+func (a TriggerAction) String() string {
+	return fmt.Sprintf("%s: %s %s", a.Event, a.Type, a.Value)
+}
+
+// FiredTriggerAction is action waiting to be executed by game scene
+type FiredTriggerAction struct {
+	TriggerAction
+	Origin rl.Vector2
+}
+
+// PollTriggerActions returns actions fired since last call
+func PollTriggerActions() []FiredTriggerAction {
+	if len(firedTriggerActions) == 0 {
+		return nil
+	}
+	actions := firedTriggerActions
+	firedTriggerActions = make([]FiredTriggerAction, 0)
+	return actions
+}
+
+// Trigger fires its actions when player enters, stays in or exits its volume
+type Trigger struct {
+	CollisionHitbox
+
+	Actions []TriggerAction
+	Once    bool // trigger is spent after first exit
+	// flag which must be set for trigger to fire, "!flag" requires it unset
+	Flag string `json:",omitempty"`
+
+	inside bool `json:"-"`
+	spent  bool `json:"-"`
+}
+
+func (p *Trigger) Draw() {
+	if DRAW_MODELS {
+		polys := p.PolygonsWithRotation()
+		for i, _ := range polys {
+			rl.DrawTriangleLines(
+				polys[i].Points[0],
+				polys[i].Points[1],
+				polys[i].Points[2],
+				rl.Orange,
+			)
+		}
+		label := fmt.Sprintf("TRIGGER (%d)", len(p.Actions))
+		if p.Flag != "" {
+			label = fmt.Sprintf("%s IF %s", label, p.Flag)
+		}
+		rl.DrawText(label, int32(p.TopLeft().X), int32(p.TopLeft().Y), 30, rl.Orange)
+	}
+
+	p.BaseEditorItem.Draw()
+}
+
+func (p *Trigger) Update(delta float32) {
+	if rl.IsKeyDown(rl.KeyLeftShift) || p.spent { // rewind does not replay events
+		return
+	}
+
+	detected, _ := p.CollisionProcessor.Detect(collision.Hitbox{
+		Polygons: p.PolygonsWithRotation(),
+		Mask:     collision.LAYER_PLAYER,
+	})
+	detected = detected && p.IsFlagConditionMet() // player is outside of disabled trigger
+
+	if !p.inside && detected {
+		p.fire(TriggerEnter)
+	}
+
+	if p.inside && detected {
+		p.fire(TriggerStay)
+	}
+
+	if p.inside && !detected {
+		p.fire(TriggerExit)
+		p.spent = p.Once
+	}
+
+	p.inside = detected
+}
+
+func (p *Trigger) fire(event TriggerEvent) {
+	for i, _ := range p.Actions {
+		if p.Actions[i].Event == event && p.Actions[i].IsAllowed() {
+			firedTriggerActions = append(firedTriggerActions, FiredTriggerAction{
+				TriggerAction: p.Actions[i],
+				Origin:        p.Center(),
+			})
+		}
+	}
+}
+
+func (p Trigger) IsFlagConditionMet() bool {
+	if strings.HasPrefix(p.Flag, "!") {
+		return !IsFlagSet(p.Flag[1:])
+	}
+	return p.Flag == "" || IsFlagSet(p.Flag)
+}
+
+func (p Trigger) IsSpent() bool {
+	return p.spent
+}
+
+// ResetState restores spent state, player is treated as outside
+func (p *Trigger) ResetState(spent bool) {
+	p.spent = spent
+	p.inside = false
+}
+
+func (p Trigger) CopyActions() []TriggerAction {
+	actions := make([]TriggerAction, len(p.Actions))
+	copy(actions, p.Actions)
+	return actions
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	autosaveId       = "autosave"
+	backupCollection = "backups"
+	backupTimeFormat = "2006-01-02_15-04-05"
+
+	MAX_LEVEL_BACKUPS = 10
+)
+
+// SaveLevelWithBackup saves level, keeps timestamped copy in backups
+// and removes autosave which is not needed anymore
+func (level *Level) SaveLevelWithBackup() {
+	level.SaveLevel()
+
+	collection := filepath.Join(level.Name, backupCollection)
+	err := db.Write(collection, time.Now().Format(backupTimeFormat), level)
+	if err != nil {
+		panic(err)
+	}
+	rotateBackups(filepath.Join(dataId, collection))
+
+	DeleteAutosave(level.Name)
+}
+
+func (level *Level) SaveAutosave() {
+	err := db.Write(level.Name, autosaveId, level)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func GetAutosave(levelName string) (Level, bool) {
+	var level Level
+	err := db.Read(levelName, autosaveId, &level)
+	if err != nil {
+		return level, false
+	}
+	level.Name = levelName
+	return level, true
+}
+
+func DeleteAutosave(levelName string) {
+	db.Delete(levelName, autosaveId) // error means there is no autosave
+}
+
+func rotateBackups(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	backups := make([]string, 0)
+	for i, _ := range entries {
+		name := entries[i].Name()
+		if strings.HasSuffix(name, ".json") {
+			backups = append(backups, name)
+		}
+	}
+
+	// timestamp names are sorted from oldest to newest
+	sort.Strings(backups)
+
+	for len(backups) > MAX_LEVEL_BACKUPS {
+		err := os.Remove(filepath.Join(dir, backups[0]))
+		if err != nil {
+			panic(err)
+		}
+		backups = backups[1:]
+	}
+}
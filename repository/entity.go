@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"ahasuerus/collision"
 	"ahasuerus/models"
+	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -9,15 +11,34 @@ import (
 const dataId = "data"
 
 type Level struct {
-	Name               string
+	Name   string
+	Title  string
+	Order  int
+	Locked bool // locked until reached in save game
+
+	Author       string
+	Description  string
+	ParTime      float32 // seconds
+	RewindBudget float32 // recommended rewind seconds
+	Thumbnail    string
+
 	Characters         []models.Npc
 	Lights             []models.Light
 	CollissionHitboxes []models.CollisionHitbox
 	Images             []models.Image
 	ParticleSources    []models.ParticleSource
+	Triggers           []models.Trigger
+	Checkpoints        []models.Checkpoint
+
+	// explicit camera bounds, zero means computed from content
+	Bounds rl.Rectangle
 
 	PlayerPos    rl.Vector2
 	PlayerShader string
+	// Physics overrides fields of default player physics profile present in JSON
+	Physics *models.PhysicsOverride `json:",omitempty"`
+	// Dash ability is unlocked in level
+	Dash bool `json:",omitempty"`
 
 	MusicTheme        string
 	MusicThemeReverse string
@@ -33,6 +54,20 @@ func GetLevel(levelName string) Level {
 	return level
 }
 
+// GetLevelInfo reads level metadata, on error info still has name as title
+func GetLevelInfo(levelName string) (LevelInfo, error) {
+	info := LevelInfo{Name: levelName, Title: levelName}
+	err := db.Read(levelName, dataId, &info)
+	if err != nil {
+		return info, err
+	}
+	info.Name = levelName
+	if info.Title == "" {
+		info.Title = levelName
+	}
+	return info, nil
+}
+
 func (level *Level) SaveLevel() {
 	err := db.Write(level.Name, dataId, level)
 	if err != nil {
@@ -40,16 +75,51 @@ func (level *Level) SaveLevel() {
 	}
 }
 
-func (level *Level) Size() rl.Vector2 {
-	var size rl.Vector2
-	for _, point := range level.Images {
-		for _, polygon := range point.BaseEditorItem.Polygons {
-			for _, polygonPoint := range polygon.Points {
-				if polygonPoint.X > size.X && polygonPoint.Y > size.Y {
-					size = polygonPoint
-				}
-			}
+func (level Level) Info() LevelInfo {
+	return LevelInfo{
+		Name:         level.Name,
+		Title:        level.Title,
+		Order:        level.Order,
+		Locked:       level.Locked,
+		Author:       level.Author,
+		Description:  level.Description,
+		ParTime:      level.ParTime,
+		RewindBudget: level.RewindBudget,
+		Thumbnail:    level.Thumbnail,
+	}
+}
+
+// GetBounds returns explicit level bounds or bounds computed from content
+func (level *Level) GetBounds() rl.Rectangle {
+	if level.Bounds.Width > 0 && level.Bounds.Height > 0 {
+		return level.Bounds
+	}
+	return level.ContentBounds()
+}
+
+func (level *Level) ContentBounds() rl.Rectangle {
+	polygons := make([]collision.Polygon, 0)
+	for i, _ := range level.Images {
+		polygons = append(polygons, level.Images[i].PolygonsWithRotation()...)
+	}
+	for i, _ := range level.CollissionHitboxes {
+		polygons = append(polygons, level.CollissionHitboxes[i].PolygonsWithRotation()...)
+	}
+
+	if len(polygons) == 0 {
+		return rl.Rectangle{}
+	}
+
+	min := polygons[0].Points[0]
+	max := min
+	for _, polygon := range polygons {
+		for _, point := range polygon.Points {
+			min.X = float32(math.Min(float64(min.X), float64(point.X)))
+			min.Y = float32(math.Min(float64(min.Y), float64(point.Y)))
+			max.X = float32(math.Max(float64(max.X), float64(point.X)))
+			max.Y = float32(math.Max(float64(max.Y), float64(point.Y)))
 		}
 	}
-	return size
+
+	return rl.NewRectangle(min.X, min.Y, max.X-min.X, max.Y-min.Y)
 }
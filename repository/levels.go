@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"os"
+	"sort"
+)
+
+// LevelInfo is a lightweight view of Level used to list levels
+// without loading the whole content
+type LevelInfo struct {
+	Name   string
+	Title  string
+	Order  int
+	Locked bool
+
+	Author       string
+	Description  string
+	ParTime      float32
+	RewindBudget float32
+	Thumbnail    string
+}
+
+func GetLevels() []LevelInfo {
+	entries, err := os.ReadDir(dataId)
+	if err != nil {
+		panic(err)
+	}
+
+	levels := make([]LevelInfo, 0)
+	for i, _ := range entries {
+		entry := entries[i]
+		if !entry.IsDir() {
+			continue
+		}
+
+		var info LevelInfo
+		err := db.Read(entry.Name(), dataId, &info)
+		if err != nil {
+			continue // not a level directory
+		}
+		info.Name = entry.Name()
+		if info.Title == "" {
+			info.Title = info.Name
+		}
+		levels = append(levels, info)
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		if levels[i].Order == levels[j].Order {
+			return levels[i].Name < levels[j].Name
+		}
+		return levels[i].Order < levels[j].Order
+	})
+
+	return levels
+}
+
+// IsNextLevel reports whether to comes after from in level order,
+// change to earlier level or hub is not a level exit
+func IsNextLevel(from, to string) bool {
+	fromIndex, toIndex := -1, -1
+	levels := GetLevels()
+	for i, _ := range levels {
+		if levels[i].Name == from {
+			fromIndex = i
+		}
+		if levels[i].Name == to {
+			toIndex = i
+		}
+	}
+	return fromIndex >= 0 && toIndex > fromIndex
+}
+
+func GetFirstLevel() LevelInfo {
+	levels := GetLevels()
+	if len(levels) == 0 {
+		panic("no levels found in " + dataId)
+	}
+	return levels[0]
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"ahasuerus/models"
+	"ahasuerus/resources"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	packId           = "packs"
+	packManifest     = "manifest.json"
+	packLevel        = "level.json"
+	packResourcesDir = "resources"
+	packImportDir    = "resources/imported"
+)
+
+type PackManifest struct {
+	Level  string
+	Title  string
+	Author string
+	Assets []string
+}
+
+// ExportLevelPack writes level and every asset it references into packs/<level>.zip
+func ExportLevelPack(levelName string) (string, error) {
+	level := GetLevel(levelName)
+
+	manifest := PackManifest{
+		Level:  level.Name,
+		Title:  level.Title,
+		Author: level.Author,
+		Assets: level.Assets(),
+	}
+
+	err := os.MkdirAll(packId, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	archivePath := filepath.Join(packId, levelName+".zip")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+
+	err = writeZipJson(w, packManifest, manifest)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeZipJson(w, packLevel, level)
+	if err != nil {
+		return "", err
+	}
+
+	for _, asset := range manifest.Assets {
+		data, err := os.ReadFile(asset)
+		if err != nil {
+			return "", fmt.Errorf("read asset %s: %w", asset, err)
+		}
+		zf, err := w.Create(asset)
+		if err != nil {
+			return "", err
+		}
+		_, err = zf.Write(data)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return archivePath, w.Close()
+}
+
+// ImportLevelPack unpacks archive into data and resources directories.
+// Conflicting level names get a numeric suffix, conflicting assets with
+// other content are placed into resources/imported/<level> and level paths are rewritten.
+func ImportLevelPack(archivePath string) (string, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	files := make(map[string]*zip.File)
+	for i, _ := range r.File {
+		files[r.File[i].Name] = r.File[i]
+	}
+
+	var manifest PackManifest
+	err = readZipJson(files, packManifest, &manifest)
+	if err != nil {
+		return "", err
+	}
+
+	var level Level
+	err = readZipJson(files, packLevel, &level)
+	if err != nil {
+		return "", err
+	}
+
+	if !isPackLevelName(manifest.Level) {
+		return "", fmt.Errorf("invalid level name %q", manifest.Level)
+	}
+	level.Name = FreeLevelName(manifest.Level)
+
+	renamed := make(map[string]string)
+	for _, asset := range manifest.Assets {
+		if !isPackAssetPath(asset) {
+			return "", fmt.Errorf("invalid asset path %s", asset)
+		}
+
+		zf, ok := files[asset]
+		if !ok {
+			return "", fmt.Errorf("asset %s not found in pack", asset)
+		}
+
+		data, err := readZipFile(zf)
+		if err != nil {
+			return "", err
+		}
+
+		target := asset
+		existing, err := os.ReadFile(target)
+		if err == nil && bytes.Equal(existing, data) {
+			continue // same asset already installed
+		}
+		if err == nil {
+			target = path.Join(packImportDir, level.Name, strings.TrimPrefix(asset, packResourcesDir+"/"))
+			renamed[asset] = target
+		}
+
+		err = os.MkdirAll(filepath.Dir(target), 0755)
+		if err != nil {
+			return "", err
+		}
+		err = os.WriteFile(target, data, 0644)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	level.RewriteAssets(func(asset string) string {
+		newPath, ok := renamed[asset]
+		if ok {
+			return newPath
+		}
+		return asset
+	})
+
+	level.SaveLevel()
+
+	return level.Name, nil
+}
+
+// Assets returns every resource path referenced by level
+func (level *Level) Assets() []string {
+	assets := make([]string, 0)
+	seen := make(map[string]bool)
+	level.RewriteAssets(func(asset string) string {
+		if !seen[asset] {
+			seen[asset] = true
+			assets = append(assets, asset)
+		}
+		return asset
+	})
+	return assets
+}
+
+// RewriteAssets replaces every non empty resource path of level with rewrite result
+func (level *Level) RewriteAssets(rewrite func(asset string) string) {
+	rewriteString := func(asset *string) {
+		if *asset != "" {
+			*asset = rewrite(*asset)
+		}
+	}
+	rewriteTexture := func(asset *resources.GameTexture) {
+		if *asset != "" {
+			*asset = resources.GameTexture(rewrite(string(*asset)))
+		}
+	}
+	rewriteShader := func(asset *resources.GameShader) {
+		if *asset != resources.UndefinedShader {
+			*asset = resources.GameShader(rewrite(string(*asset)))
+		}
+	}
+
+	for i, _ := range level.Images {
+		rewriteTexture(&level.Images[i].ImageTexture)
+		rewriteShader(&level.Images[i].ImageShader)
+	}
+	for i, _ := range level.Characters {
+		rewriteString(&level.Characters[i].BgImagePath)
+	}
+	for i, _ := range level.ParticleSources {
+		rewriteTexture(&level.ParticleSources[i].ParticleTexture)
+		rewriteShader(&level.ParticleSources[i].ParticleShader)
+	}
+	for i, _ := range level.Triggers {
+		actions := level.Triggers[i].Actions
+		for j, _ := range actions {
+			if actions[j].Type == models.StartMusicAction {
+				rewriteString(&actions[j].Value)
+				rewriteString(&actions[j].Reverse)
+			}
+		}
+	}
+	rewriteString(&level.PlayerShader)
+	rewriteString(&level.MusicTheme)
+	rewriteString(&level.MusicThemeReverse)
+	rewriteString(&level.Thumbnail)
+}
+
+// FreeLevelName returns name or name with numeric suffix not used by any level
+func FreeLevelName(name string) string {
+	candidate := name
+	for i := 2; ; i++ {
+		_, err := os.Stat(filepath.Join(dataId, candidate))
+		if errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+}
+
+// isPackLevelName accepts plain directory names, level is saved to data/<name>
+func isPackLevelName(name string) bool {
+	return name != "" && name != "." && !strings.ContainsAny(name, `/\`) && !strings.Contains(name, "..")
+}
+
+func isPackAssetPath(asset string) bool {
+	clean := path.Clean(asset)
+	return clean == asset && strings.HasPrefix(clean, packResourcesDir+"/") && !strings.Contains(clean, "..")
+}
+
+func writeZipJson(w *zip.Writer, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	zf, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = zf.Write(data)
+	return err
+}
+
+func readZipJson(files map[string]*zip.File, name string, v interface{}) error {
+	zf, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%s not found in pack", name)
+	}
+	data, err := readZipFile(zf)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func readZipFile(zf *zip.File) ([]byte, error) {
+	rc, err := zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
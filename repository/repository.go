@@ -5,7 +5,8 @@ import (
 )
 
 var (
-	db *scribble.Driver
+	db    *scribble.Driver
+	saves *scribble.Driver
 )
 
 func init() {
@@ -14,4 +15,8 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	saves, err = scribble.New(saveId, nil)
+	if err != nil {
+		panic(err)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	saveId         = "saves"
+	saveCollection = "slots"
+
+	SAVE_SLOTS = 3
+)
+
+type LevelCompletion struct {
+	BestTime   float32
+	BestRewind float32
+	Completed  int
+}
+
+// CheckpointState is level state recorded when checkpoint is reached
+type CheckpointState struct {
+	Id             string
+	PlayerPos      rl.Vector2
+	Flags          []string
+	CollectedItems []string
+	// npc id -> NpcDialog.CurrentInteraction
+	Dialogues map[string]uint
+	// ids of triggers already spent
+	SpentTriggers []string
+}
+
+func (c CheckpointState) IsTriggerSpent(id string) bool {
+	for i, _ := range c.SpentTriggers {
+		if c.SpentTriggers[i] == id {
+			return true
+		}
+	}
+	return false
+}
+
+type SaveGame struct {
+	Slot  int
+	Level string
+
+	// zero position means level default PlayerPos
+	PlayerPos rl.Vector2
+
+	// npc id -> NpcDialog.CurrentInteraction
+	Dialogues      map[string]uint
+	CollectedItems []string
+	UnlockedLevels []string
+	// flags set by triggers
+	Flags []string
+	// id of last reached checkpoint in Level
+	Checkpoint string `json:",omitempty"`
+	// level state of Checkpoint, saves without it snapshot level when loaded
+	CheckpointState *CheckpointState `json:",omitempty"`
+
+	// level name -> best completion
+	Completions map[string]LevelCompletion
+
+	PlayTime float32
+	SavedAt  time.Time
+}
+
+func NewSaveGame(slot int, level string) SaveGame {
+	return SaveGame{
+		Slot:           slot,
+		Level:          level,
+		Dialogues:      make(map[string]uint),
+		CollectedItems: make([]string, 0),
+		UnlockedLevels: []string{level},
+		Flags:          make([]string, 0),
+		Completions:    make(map[string]LevelCompletion),
+	}
+}
+
+func GetSave(slot int) (SaveGame, bool) {
+	var save SaveGame
+	err := saves.Read(saveCollection, slotResource(slot), &save)
+	if err != nil {
+		return save, false
+	}
+	if save.Dialogues == nil {
+		save.Dialogues = make(map[string]uint)
+	}
+	if save.Completions == nil {
+		save.Completions = make(map[string]LevelCompletion)
+	}
+	save.Slot = slot
+	return save, true
+}
+
+func GetSaves() []SaveGame {
+	result := make([]SaveGame, 0, SAVE_SLOTS)
+	for slot := 1; slot <= SAVE_SLOTS; slot++ {
+		save, ok := GetSave(slot)
+		if !ok {
+			save = NewSaveGame(slot, "")
+		}
+		result = append(result, save)
+	}
+	return result
+}
+
+// FreeSaveSlot returns first slot without saved game
+func FreeSaveSlot() (int, bool) {
+	for _, save := range GetSaves() {
+		if save.IsEmpty() {
+			return save.Slot, true
+		}
+	}
+	return 0, false
+}
+
+func GetLatestSave() (SaveGame, bool) {
+	var latest SaveGame
+	found := false
+	for _, save := range GetSaves() {
+		if save.Level == "" {
+			continue
+		}
+		if !found || save.SavedAt.After(latest.SavedAt) {
+			latest = save
+			found = true
+		}
+	}
+	return latest, found
+}
+
+func (save *SaveGame) Save() {
+	save.SavedAt = time.Now()
+	err := saves.Write(saveCollection, slotResource(save.Slot), save)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (save *SaveGame) CompleteLevel(level string, time, rewind float32) {
+	completion, ok := save.Completions[level]
+	if !ok || time < completion.BestTime {
+		completion.BestTime = time
+	}
+	if !ok || rewind < completion.BestRewind {
+		completion.BestRewind = rewind
+	}
+	completion.Completed++
+	save.Completions[level] = completion
+}
+
+func (save *SaveGame) UnlockLevel(level string) {
+	if !save.IsLevelUnlocked(level) {
+		save.UnlockedLevels = append(save.UnlockedLevels, level)
+	}
+}
+
+func (save SaveGame) IsLevelUnlocked(level string) bool {
+	for i, _ := range save.UnlockedLevels {
+		if save.UnlockedLevels[i] == level {
+			return true
+		}
+	}
+	return false
+}
+
+func (save *SaveGame) ClearCheckpoint() {
+	save.Checkpoint = ""
+	save.CheckpointState = nil
+}
+
+func (save SaveGame) IsEmpty() bool {
+	return save.Level == ""
+}
+
+func (save SaveGame) PlayTimeString() string {
+	return FormatTime(save.PlayTime)
+}
+
+func FormatTime(seconds float32) string {
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total/60%60, total%60)
+}
+
+func slotResource(slot int) string {
+	return fmt.Sprintf("slot%d", slot)
+}
@@ -31,6 +31,12 @@ const (
 	ParticleFogTexture      GameTexture = "resources/particles/fog2.png"
 )
 
+type AnimationGraph string
+
+const (
+	PlayerAnimationGraph AnimationGraph = "resources/animations/player.json"
+)
+
 type GameShader string
 
 const (
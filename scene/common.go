@@ -3,6 +3,7 @@ package scene
 import (
 	"ahasuerus/config"
 	"ahasuerus/models"
+	"ahasuerus/repository"
 	"fmt"
 	"math"
 
@@ -11,13 +12,12 @@ import (
 
 type SceneId string
 
+// levels are not listed here, any other id is loaded from data directory
 const (
 	Undefined SceneId = ""
 	Menu SceneId = "menu"
-	Start SceneId = "start"
 	Editor SceneId = "editor"
 	Close SceneId = "close"
-	Level1 SceneId = "level1"
 )
 
 var (
@@ -32,7 +32,7 @@ func GetScene(id SceneId) models.Scene {
 		return scene
 	}
 
-	drawLoadScene()
+	drawLoadScene(id)
 
 	switch id {
 	case Menu:
@@ -41,7 +41,9 @@ func GetScene(id SceneId) models.Scene {
 		UnloadScene(lastScene)
 		scene = NewEditScene(string(lastScene), lastScene)
 	default:
-		UnloadScene(lastScene)
+		if _, ok := sceneMap[lastScene]; ok && lastScene != Menu {
+			UnloadScene(lastScene)
+		}
 		scene = NewGameScene(string(id))
 	}
 
@@ -57,9 +59,23 @@ func GetScene(id SceneId) models.Scene {
 	return scene
 }
 
-func drawLoadScene() {
+func drawLoadScene(id SceneId) {
 	rl.BeginDrawing()
 	rl.ClearBackground(rl.Black)
+	if id != Menu && id != Editor && id != Close {
+		info, err := repository.GetLevelInfo(string(id))
+		if err != nil {
+			fmt.Println("WARN: Level info not loaded:", err)
+		}
+		models.DrawSdfText(info.Title, rl.NewVector2(WIDTH/10, HEIGHT/3), 100, rl.White)
+		if info.Author != "" {
+			models.DrawSdfText("by "+info.Author, rl.NewVector2(WIDTH/10, HEIGHT/3+110), 50, rl.Gray)
+		}
+		models.DrawSdfText(info.Description, rl.NewVector2(WIDTH/10, HEIGHT/3+180), 50, rl.White)
+		if info.ParTime > 0 {
+			models.DrawSdfText("par "+repository.FormatTime(info.ParTime), rl.NewVector2(WIDTH/10, HEIGHT/3+250), 50, rl.Orange)
+		}
+	}
 	rl.EndDrawing()
 }
 
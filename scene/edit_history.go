@@ -0,0 +1,326 @@
+package scene
+
+import (
+	"ahasuerus/collision"
+	"ahasuerus/container"
+	"ahasuerus/models"
+	"ahasuerus/repository"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type editCommand interface {
+	undo(c *container.ObjectResourceContainer)
+	redo(c *container.ObjectResourceContainer)
+}
+
+// editHistory keeps undo and redo stacks of editor actions
+type editHistory struct {
+	undoStack []editCommand
+	redoStack []editCommand
+
+	// changes counts push, undo and redo to detect unsaved changes
+	changes int
+
+	editItem   *models.BaseEditorItem
+	editBefore models.EditorItemState
+}
+
+func newEditHistory() *editHistory {
+	return &editHistory{
+		undoStack: make([]editCommand, 0),
+		redoStack: make([]editCommand, 0),
+	}
+}
+
+func (h *editHistory) push(command editCommand) {
+	h.undoStack = append(h.undoStack, command)
+	h.redoStack = h.redoStack[:0]
+	h.changes++
+}
+
+func (h *editHistory) undo(c *container.ObjectResourceContainer) {
+	if len(h.undoStack) == 0 {
+		return
+	}
+	command := h.undoStack[len(h.undoStack)-1]
+	h.undoStack = h.undoStack[:len(h.undoStack)-1]
+	command.undo(c)
+	h.redoStack = append(h.redoStack, command)
+	h.changes++
+}
+
+func (h *editHistory) redo(c *container.ObjectResourceContainer) {
+	if len(h.redoStack) == 0 {
+		return
+	}
+	command := h.redoStack[len(h.redoStack)-1]
+	h.redoStack = h.redoStack[:len(h.redoStack)-1]
+	command.redo(c)
+	h.undoStack = append(h.undoStack, command)
+	h.changes++
+}
+
+// beginEdit remembers item geometry when item selection starts
+func (h *editHistory) beginEdit(item *models.BaseEditorItem) {
+	if h.editItem == item {
+		return
+	}
+	h.endEdit()
+	h.editItem = item
+	h.editBefore = item.GetState()
+}
+
+// endEdit records move, resize and rotate made during selection
+func (h *editHistory) endEdit() {
+	if h.editItem == nil {
+		return
+	}
+	after := h.editItem.GetState()
+	if after != h.editBefore {
+		h.push(&itemStateCommand{
+			item:   h.editItem,
+			before: h.editBefore,
+			after:  after,
+		})
+	}
+	h.editItem = nil
+}
+
+// pushItemCommand records command which already changed the edited item,
+// item geometry snapshot starts over so endEdit does not record it twice
+func (h *editHistory) pushItemCommand(command editCommand) {
+	h.editItem = nil
+	h.push(command)
+}
+
+type itemStateCommand struct {
+	item   *models.BaseEditorItem
+	before models.EditorItemState
+	after  models.EditorItemState
+}
+
+func (cmd *itemStateCommand) undo(c *container.ObjectResourceContainer) {
+	cmd.item.SetState(cmd.before)
+}
+
+func (cmd *itemStateCommand) redo(c *container.ObjectResourceContainer) {
+	cmd.item.SetState(cmd.after)
+}
+
+type addObjectCommand struct {
+	obj   models.Object
+	index int
+}
+
+func (cmd *addObjectCommand) undo(c *container.ObjectResourceContainer) {
+	c.RemoveObject(cmd.obj)
+}
+
+func (cmd *addObjectCommand) redo(c *container.ObjectResourceContainer) {
+	c.InsertObject(cmd.index, cmd.obj)
+}
+
+type removeObjectCommand struct {
+	obj   models.Object
+	index int
+}
+
+func (cmd *removeObjectCommand) undo(c *container.ObjectResourceContainer) {
+	c.InsertObject(cmd.index, cmd.obj)
+}
+
+func (cmd *removeObjectCommand) redo(c *container.ObjectResourceContainer) {
+	c.RemoveObject(cmd.obj)
+}
+
+type moveOrderCommand struct {
+	obj       models.Object
+	item      *models.BaseEditorItem
+	up        bool
+	fromIndex int
+	toIndex   int
+}
+
+func (cmd *moveOrderCommand) undo(c *container.ObjectResourceContainer) {
+	cmd.move(c, !cmd.up)
+}
+
+func (cmd *moveOrderCommand) redo(c *container.ObjectResourceContainer) {
+	cmd.move(c, cmd.up)
+}
+
+func (cmd *moveOrderCommand) move(c *container.ObjectResourceContainer, up bool) {
+	if cmd.fromIndex != cmd.toIndex {
+		if up {
+			c.MoveUp(cmd.obj)
+		} else {
+			c.MoveDown(cmd.obj)
+		}
+	}
+	if up {
+		cmd.item.DrawIndex--
+	} else {
+		cmd.item.DrawIndex++
+	}
+}
+
+// shapeState is geometry of collision hitbox including its polygon outline
+type shapeState struct {
+	state models.EditorItemState
+	shape []rl.Vector2
+}
+
+func hitboxShapeState(hitbox *models.CollisionHitbox) shapeState {
+	shape := make([]rl.Vector2, len(hitbox.Shape))
+	copy(shape, hitbox.Shape)
+	return shapeState{
+		state: hitbox.GetState(),
+		shape: shape,
+	}
+}
+
+func (s shapeState) equals(other shapeState) bool {
+	if s.state != other.state || len(s.shape) != len(other.shape) {
+		return false
+	}
+	for i, _ := range s.shape {
+		if s.shape[i] != other.shape[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type shapeCommand struct {
+	hitbox *models.CollisionHitbox
+	before shapeState
+	after  shapeState
+}
+
+func (cmd *shapeCommand) undo(c *container.ObjectResourceContainer) {
+	cmd.apply(cmd.before)
+}
+
+func (cmd *shapeCommand) redo(c *container.ObjectResourceContainer) {
+	cmd.apply(cmd.after)
+}
+
+func (cmd *shapeCommand) apply(s shapeState) {
+	cmd.hitbox.SetState(s.state)
+	cmd.hitbox.Shape = make([]rl.Vector2, len(s.shape))
+	copy(cmd.hitbox.Shape, s.shape)
+}
+
+type oneWayCommand struct {
+	hitbox *models.CollisionHitbox
+}
+
+func (cmd *oneWayCommand) undo(c *container.ObjectResourceContainer) {
+	cmd.hitbox.OneWay = !cmd.hitbox.OneWay
+}
+
+func (cmd *oneWayCommand) redo(c *container.ObjectResourceContainer) {
+	cmd.hitbox.OneWay = !cmd.hitbox.OneWay
+}
+
+type layerCommand struct {
+	hitbox *models.CollisionHitbox
+	before collision.Layer
+	after  collision.Layer
+}
+
+func (cmd *layerCommand) undo(c *container.ObjectResourceContainer) {
+	cmd.hitbox.Layer = cmd.before
+}
+
+func (cmd *layerCommand) redo(c *container.ObjectResourceContainer) {
+	cmd.hitbox.Layer = cmd.after
+}
+
+type materialCommand struct {
+	hitbox *models.CollisionHitbox
+	before *collision.Material
+	after  *collision.Material
+}
+
+func (cmd *materialCommand) undo(c *container.ObjectResourceContainer) {
+	cmd.hitbox.Material = cmd.before
+}
+
+func (cmd *materialCommand) redo(c *container.ObjectResourceContainer) {
+	cmd.hitbox.Material = cmd.after
+}
+
+type dashCommand struct {
+	level *repository.Level
+}
+
+func (cmd *dashCommand) undo(c *container.ObjectResourceContainer) {
+	cmd.level.Dash = !cmd.level.Dash
+}
+
+func (cmd *dashCommand) redo(c *container.ObjectResourceContainer) {
+	cmd.level.Dash = !cmd.level.Dash
+}
+
+type platformCommand struct {
+	hitbox *models.CollisionHitbox
+	before models.PlatformMotion
+	after  models.PlatformMotion
+}
+
+func (cmd *platformCommand) undo(c *container.ObjectResourceContainer) {
+	cmd.hitbox.PlatformMotion = cmd.before.Copy()
+}
+
+func (cmd *platformCommand) redo(c *container.ObjectResourceContainer) {
+	cmd.hitbox.PlatformMotion = cmd.after.Copy()
+}
+
+type triggerState struct {
+	actions []models.TriggerAction
+	once    bool
+	flag    string
+}
+
+func triggerStateOf(trigger *models.Trigger) triggerState {
+	return triggerState{
+		actions: trigger.CopyActions(),
+		once:    trigger.Once,
+		flag:    trigger.Flag,
+	}
+}
+
+func (s triggerState) equals(other triggerState) bool {
+	if s.once != other.once || s.flag != other.flag || len(s.actions) != len(other.actions) {
+		return false
+	}
+	for i, _ := range s.actions {
+		if s.actions[i] != other.actions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type triggerCommand struct {
+	trigger *models.Trigger
+	before  triggerState
+	after   triggerState
+}
+
+func (cmd *triggerCommand) undo(c *container.ObjectResourceContainer) {
+	cmd.apply(cmd.before)
+}
+
+func (cmd *triggerCommand) redo(c *container.ObjectResourceContainer) {
+	cmd.apply(cmd.after)
+}
+
+func (cmd *triggerCommand) apply(s triggerState) {
+	cmd.trigger.Actions = make([]models.TriggerAction, len(s.actions))
+	copy(cmd.trigger.Actions, s.actions)
+	cmd.trigger.Once = s.once
+	cmd.trigger.Flag = s.flag
+}
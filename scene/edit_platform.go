@@ -0,0 +1,124 @@
+package scene
+
+import (
+	"ahasuerus/controls"
+	"ahasuerus/models"
+	"fmt"
+
+	rg "github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	pathTimeEditField = 0
+	spinEditField     = 1
+)
+
+// drawPlatformHub edits path of kinematic platform, waypoints are placed with mouse
+func (s *EditScene) drawPlatformHub(hitbox *models.CollisionHitbox, bc *models.Counter) {
+	before := hitbox.PlatformMotion.Copy()
+
+	addWaypoint := rg.Button(s.controlRect(bc), "ADD WAYPOINT")
+	clearPath := rg.Button(s.controlRect(bc), "CLEAR PATH")
+
+	column := models.NewCounter()
+
+	shownPathTime := int32(hitbox.PathTime)
+	if shownPathTime == 0 {
+		shownPathTime = models.DEFAULT_PATH_TIME
+	}
+	pathTime := shownPathTime
+	s.itemSpinner(&column, pathTimeEditField, "PATH TIME(S)", &pathTime, 1, 600)
+	if pathTime != shownPathTime {
+		hitbox.PathTime = float32(pathTime)
+	}
+
+	shownSpin := int32(hitbox.RotationSpeed)
+	spin := shownSpin
+	s.itemSpinner(&column, spinEditField, "SPIN(DEG/S)", &spin, -720, 720)
+	if spin != shownSpin {
+		hitbox.RotationSpeed = float32(spin)
+	}
+
+	labelRect, fieldRect := s.secondColumnRow(&column)
+	rg.Label(labelRect, "EASE")
+	if rg.Button(fieldRect, platformEase(hitbox)) {
+		hitbox.PathEase = nextPlatformEase(platformEase(hitbox))
+	}
+
+	_, loopRect := s.secondColumnRow(&column)
+	loopRect.Width = loopRect.Height
+	hitbox.PathLoop = rg.CheckBox(loopRect, "LOOP PATH", hitbox.PathLoop)
+
+	if clearPath {
+		hitbox.Path = nil
+	}
+
+	after := hitbox.PlatformMotion
+	if !before.Equals(after) {
+		s.history.pushItemCommand(&platformCommand{
+			hitbox: hitbox,
+			before: before,
+			after:  after.Copy(),
+		})
+	}
+
+	if addWaypoint && s.waypointHitbox == nil && s.vertexEditHitbox == nil {
+		s.startWaypointPlacing(hitbox)
+	}
+}
+
+func platformEase(hitbox *models.CollisionHitbox) string {
+	if hitbox.PathEase == "" {
+		return models.DEFAULT_PATH_EASE
+	}
+	return hitbox.PathEase
+}
+
+func nextPlatformEase(current string) string {
+	for i, _ := range models.PLATFORM_EASES {
+		if models.PLATFORM_EASES[i] == current {
+			return models.PLATFORM_EASES[(i+1)%len(models.PLATFORM_EASES)]
+		}
+	}
+	return models.PLATFORM_EASES[0]
+}
+
+func (s *EditScene) startWaypointPlacing(hitbox *models.CollisionHitbox) {
+	s.waypointHitbox = hitbox
+
+	last := hitbox.TopLeft()
+	if len(hitbox.Path) > 0 {
+		last = rl.Vector2Add(last, hitbox.Path[len(hitbox.Path)-1])
+	}
+
+	controls.DisableCursor(105)
+	controls.SetMousePosition(int(last.X), int(last.Y), 106)
+}
+
+// processWaypointPlacing adds waypoint where platform top left should be on mouse click
+func (s *EditScene) processWaypointPlacing() {
+	hitbox := s.waypointHitbox
+	if !hitbox.EditSelected {
+		s.waypointHitbox = nil
+		return
+	}
+
+	mousePos := rl.GetMousePosition()
+	rl.DrawRectangleLines(int32(mousePos.X), int32(mousePos.Y), int32(hitbox.Width()), int32(hitbox.Height()), rl.Green)
+	rl.DrawCircle(int32(mousePos.X), int32(mousePos.Y), 10, rl.Red)
+	rl.DrawText(fmt.Sprintf("\nWaypoint %d [MOUSE]", len(hitbox.Path)+1), int32(mousePos.X), int32(mousePos.Y+40), 40, rl.Red)
+
+	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+		before := hitbox.PlatformMotion.Copy()
+		hitbox.Path = append(before.Copy().Path, rl.Vector2Subtract(mousePos, hitbox.TopLeft()))
+		s.history.pushItemCommand(&platformCommand{
+			hitbox: hitbox,
+			before: before,
+			after:  hitbox.PlatformMotion.Copy(),
+		})
+
+		s.waypointHitbox = nil
+		controls.EnableCursor(132)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"ahasuerus/repository"
 	"ahasuerus/resources"
 	"fmt"
+	"strconv"
 
 	"strings"
 
@@ -22,6 +23,12 @@ const (
 	editorControlRectHeight = float32(60)
 	editorControlMarginLeft = 50
 	maxTextSize             = 200
+	layerTogglesPerRow      = 3
+
+	noLevelInfoEditField = -1
+	noItemEditField      = -1 // fields of selected item hub
+
+	EDITOR_AUTOSAVE_SECONDS = 60
 )
 
 type EditScene struct {
@@ -34,6 +41,28 @@ type EditScene struct {
 	selectedGameObjectsItem []models.EditorSelectedItem
 
 	editMenuGameImageDropMode bool
+	editMenuLevelInfoMode     bool
+	levelInfoEditField        int
+	levelInfoDraft            string // text of edited number field, may be not a number yet
+
+	history      *editHistory
+	savedChanges int
+	savedInfo    repository.LevelInfo
+
+	vertexEditHitbox *models.CollisionHitbox
+	vertexDragIndex  int
+	vertexBefore     shapeState
+
+	waypointHitbox *models.CollisionHitbox
+	triggerDraft   models.TriggerAction
+	itemEditField  int
+	itemDraft      string // text of edited number field of selected item
+
+	autosaveTimer    float32
+	autosavedChanges int
+	autosavedInfo    repository.LevelInfo // level info edits are not in history
+	recoveryPrompt   bool
+	exitPrompt       bool
 
 	onScreenQueue chan models.Object
 	screenScale float32
@@ -49,26 +78,49 @@ func NewEditScene(
 
 	level := repository.GetLevel(sceneName)
 	
-	levelSize := level.Size()
+	levelBounds := level.GetBounds()
 
-	screenScale := HEIGHT/levelSize.Y
+	screenScale := HEIGHT/levelBounds.Height
 
 	camera := rl.NewCamera2D(
 		rl.NewVector2(WIDTH/2, HEIGHT/2),
-		rl.NewVector2(WIDTH/2, levelSize.Y/2),
+		rl.NewVector2(levelBounds.X+WIDTH/2, levelBounds.Y+levelBounds.Height/2),
 		0, screenScale)
 
 	scene := &EditScene{
-		level:                   level,
 		camera:                  &camera,
 		sourceScene:             sourceScene,
-		worldContainer:          container.NewObjectResourceContainer(),
 		editCameraSpeed:         5,
-		selectedGameObjectsItem: make([]models.EditorSelectedItem, 0),
 		onScreenQueue:  make(chan models.Object, 2),
 		screenScale: screenScale,
+		levelInfoEditField: noLevelInfoEditField,
+		vertexDragIndex: noVertex,
+		itemEditField: noItemEditField,
 	}
 
+	scene.loadLevel(level)
+
+	_, scene.recoveryPrompt = repository.GetAutosave(sceneName)
+
+	controls.SetMousePosition(int(scene.camera.Target.X), int(scene.camera.Target.Y), 661)
+
+	models.DRAW_MODELS = true
+
+	return scene
+}
+
+func (scene *EditScene) loadLevel(level repository.Level) {
+	scene.level = level
+	scene.worldContainer = container.NewObjectResourceContainer()
+	scene.selectedGameObjectsItem = make([]models.EditorSelectedItem, 0)
+	scene.history = newEditHistory()
+	scene.vertexEditHitbox = nil
+	scene.waypointHitbox = nil
+	scene.savedChanges = 0
+	scene.savedInfo = level.Info()
+	scene.autosavedChanges = 0
+	scene.autosavedInfo = level.Info()
+
 	worldImages := scene.level.Images
 	for i, _ := range worldImages {
 		img := worldImages[i]
@@ -100,13 +152,23 @@ func NewEditScene(
 		scene.worldContainer.AddObjectResource(&particle)
 	}
 
-	controls.SetMousePosition(int(scene.camera.Target.X), int(scene.camera.Target.Y), 661)
+	triggers := scene.level.Triggers
+	for i, _ := range triggers {
+		trigger := triggers[i]
+		scene.worldContainer.AddObjectResource(&trigger)
+	}
 
-	scene.worldContainer.Load()
+	checkpoints := scene.level.Checkpoints
+	for i, _ := range checkpoints {
+		checkpoint := checkpoints[i]
+		scene.worldContainer.AddObjectResource(&checkpoint)
+	}
 
-	models.DRAW_MODELS = true
+	if level.Bounds.Width > 0 && level.Bounds.Height > 0 {
+		scene.worldContainer.AddObject(models.NewLevelBounds(level.Bounds))
+	}
 
-	return scene
+	scene.worldContainer.Load()
 }
 
 func (s EditScene) Run() models.Scene {
@@ -119,11 +181,26 @@ func (s EditScene) Run() models.Scene {
 		delta := rl.GetFrameTime()
 		s.camera.Zoom += rl.GetMouseWheelMove() * 0.05
 
-		if rl.IsKeyDown(rl.KeyF2) && !s.editorHubEnabled {
-			break
+		if s.recoveryPrompt {
+			s.processRecoveryPrompt()
+		} else if s.exitPrompt {
+			if s.processExitPrompt() {
+				rl.EndDrawing()
+				break
+			}
+		} else {
+			if rl.IsKeyPressed(rl.KeyF2) && !s.editorHubEnabled && !s.isTextEditing() {
+				if !s.isDirty() {
+					rl.EndDrawing()
+					break
+				}
+				s.exitPrompt = true
+			}
+
+			s.processInputs()
 		}
 
-		s.processInputs()
+		s.updateAutosave(delta)
 
 		rl.BeginMode2D(*s.camera)
 
@@ -138,6 +215,14 @@ func (s EditScene) Run() models.Scene {
 			s.processEditorGameObjectSelection()
 		}
 
+		if s.vertexEditHitbox != nil {
+			s.processVertexEditing()
+		}
+
+		if s.waypointHitbox != nil {
+			s.processWaypointPlacing()
+		}
+
 		rl.EndMode2D()
 
 		for len(s.onScreenQueue) > 0 {
@@ -152,12 +237,23 @@ func (s EditScene) Run() models.Scene {
 
 		models.NewText(10, 50).
 			SetFontSize(40).
-			SetColor(rl.Red).SetData(fmt.Sprintf("edit mode[movement(arrow keys), cam.speed(+,-,%.1f), save(F10), menu(M), off menu(N), exit(F2)]", s.editCameraSpeed)).
+			SetColor(rl.Red).SetData(fmt.Sprintf("edit mode[movement(arrow keys), cam.speed(+,-,%.1f), save(F10), undo(Ctrl+Z), redo(Ctrl+Y), menu(M), off menu(N), exit(F2)]", s.editCameraSpeed)).
 			Draw()
 
+		if s.recoveryPrompt {
+			s.drawPrompt("UNSAVED CHANGES FOUND. RECOVER? [Y]ES / [N]O")
+		} else if s.exitPrompt {
+			s.drawPrompt("UNSAVED CHANGES. SAVE(F10) / DISCARD(F9) / STAY(F11)")
+		}
+
 		rl.EndDrawing()
 	}
 
+	if rl.WindowShouldClose() && s.isDirty() {
+		newLevel := s.levelFromEditor()
+		newLevel.SaveAutosave() // recovered on next editor open
+	}
+
 	s.Unload()
 	return GetScene(s.sourceScene)
 }
@@ -202,8 +298,11 @@ func (s *EditScene) processEditorGameObjectSelection() {
 	for i, _ := range s.selectedGameObjectsItem {
 		ei := s.selectedGameObjectsItem[i]
 		if ei.Selected {
+			s.history.beginEdit(ei.Item.GetBaseEditorItem())
 			processResult := ei.Item.ProcessEditorSelection()
 			if processResult.Finished {
+				s.history.endEdit()
+				s.itemEditField = noItemEditField
 				s.editorHubEnabled = false
 				s.selectedGameObjectsItem[i].Selected = false
 				if processResult.DisableCursor {
@@ -228,6 +327,77 @@ func (s EditScene) hasAnySelectedGameObjectEditorItem() (bool, models.EditorItem
 }
 
 func (s *EditScene) saveEditor() {
+	newLevel := s.levelFromEditor()
+	newLevel.SaveLevelWithBackup()
+	s.savedChanges = s.history.changes
+	s.savedInfo = s.level.Info()
+}
+
+func (s EditScene) isDirty() bool {
+	return s.history.changes != s.savedChanges || s.level.Info() != s.savedInfo
+}
+
+func (s *EditScene) updateAutosave(delta float32) {
+	s.autosaveTimer += delta
+	if s.autosaveTimer < EDITOR_AUTOSAVE_SECONDS {
+		return
+	}
+	s.autosaveTimer = 0
+
+	changed := s.history.changes != s.autosavedChanges || s.level.Info() != s.autosavedInfo
+	if s.isDirty() && changed {
+		newLevel := s.levelFromEditor()
+		newLevel.SaveAutosave()
+		s.autosavedChanges = s.history.changes
+		s.autosavedInfo = s.level.Info()
+		s.showEditorMessage("AUTOSAVED")
+	}
+}
+
+func (s *EditScene) processRecoveryPrompt() {
+	if rl.IsKeyPressed(rl.KeyY) {
+		autosave, ok := repository.GetAutosave(s.level.Name)
+		if ok {
+			s.worldContainer.Unload()
+			s.loadLevel(autosave)
+			s.savedChanges = -1 // recovered level differs from saved one
+		}
+		s.recoveryPrompt = false
+	}
+
+	if rl.IsKeyPressed(rl.KeyN) {
+		repository.DeleteAutosave(s.level.Name)
+		s.recoveryPrompt = false
+	}
+}
+
+// processExitPrompt returns true when editor should be closed
+func (s *EditScene) processExitPrompt() bool {
+	if rl.IsKeyPressed(rl.KeyF10) {
+		s.saveEditor()
+		return true
+	}
+
+	if rl.IsKeyPressed(rl.KeyF9) {
+		repository.DeleteAutosave(s.level.Name)
+		return true
+	}
+
+	if rl.IsKeyPressed(rl.KeyF11) {
+		s.exitPrompt = false
+	}
+
+	return false
+}
+
+func (s EditScene) drawPrompt(text string) {
+	fontSize := int32(60)
+	textWidth := rl.MeasureText(text, fontSize)
+	rl.DrawRectangle(0, int32(HEIGHT)/2-fontSize, int32(WIDTH), fontSize*3, rl.NewColor(0, 0, 0, 200))
+	rl.DrawText(text, int32(WIDTH)/2-textWidth/2, int32(HEIGHT)/2, fontSize, rl.Red)
+}
+
+func (s *EditScene) levelFromEditor() repository.Level {
 	newLevel := s.level
 
 	newLevel.Characters = []models.Npc{}
@@ -235,6 +405,9 @@ func (s *EditScene) saveEditor() {
 	newLevel.CollissionHitboxes = []models.CollisionHitbox{}
 	newLevel.Images = []models.Image{}
 	newLevel.ParticleSources = []models.ParticleSource{}
+	newLevel.Triggers = []models.Trigger{}
+	newLevel.Checkpoints = []models.Checkpoint{}
+	newLevel.Bounds = rl.Rectangle{}
 
 	s.worldContainer.ForEachObject(func(obj models.Object) {
 		editorItem, ok := obj.(models.EditorItem)
@@ -265,9 +438,43 @@ func (s *EditScene) saveEditor() {
 				newLevel.ParticleSources = append(newLevel.ParticleSources, *particleSource)
 			}
 
+			trigger, ok := editorItem.(*models.Trigger)
+			if ok {
+				newLevel.Triggers = append(newLevel.Triggers, *trigger)
+			}
+
+			checkpoint, ok := editorItem.(*models.Checkpoint)
+			if ok {
+				newLevel.Checkpoints = append(newLevel.Checkpoints, *checkpoint)
+			}
+
+			levelBounds, ok := editorItem.(*models.LevelBounds)
+			if ok {
+				newLevel.Bounds = levelBounds.Rectangle()
+			}
+
 		}
 	})
-	newLevel.SaveLevel()
+	return newLevel
+}
+
+func (s *EditScene) pushAddObject(obj models.Object) {
+	s.history.push(&addObjectCommand{
+		obj:   obj,
+		index: s.worldContainer.IndexOf(obj),
+	})
+}
+
+func (s *EditScene) showEditorMessage(message string) {
+	s.worldContainer.AddObject(
+		models.NewText(int32(s.camera.Target.X-s.camera.Offset.X+WIDTH/2), int32(s.camera.Target.Y-s.camera.Offset.Y+HEIGHT/2)).
+			SetData(message).
+			SetFontSize(60).
+			SetColor(rl.Red).
+			WithExpire(3, func(t *models.Text) {
+				s.worldContainer.RemoveObject(t)
+			}),
+	)
 }
 
 func (s *EditScene) drawEditorHub() {
@@ -287,6 +494,11 @@ func (s *EditScene) drawMainHub() {
 	newLightBox := rg.Button(s.controlRect(&bc), "NEW LIGHTBOX")
 	newNpc := rg.Button(s.controlRect(&bc), "NEW NPC")
 	newParticleSource := rg.Button(s.controlRect(&bc), "PARTICLES")
+	newTrigger := rg.Button(s.controlRect(&bc), "NEW TRIGGER")
+	newCheckpoint := rg.Button(s.controlRect(&bc), "NEW CHECKPOINT")
+	levelInfo := rg.Button(s.controlRect(&bc), "LEVEL INFO")
+	exportPack := rg.Button(s.controlRect(&bc), "EXPORT PACK")
+	levelBounds := rg.Button(s.controlRect(&bc), "LEVEL BOUNDS")
 
 	toggleModelsDrawText := "HIDE COLLISSION"
 	if !models.DRAW_MODELS {
@@ -321,6 +533,7 @@ func (s *EditScene) drawMainHub() {
 			s.worldContainer.AddObjectResource(
 				image,
 			)
+			s.pushAddObject(image)
 
 			s.editMenuGameImageDropMode = false
 		}
@@ -331,30 +544,36 @@ func (s *EditScene) drawMainHub() {
 		s.editMenuGameImageDropMode = true
 	}
 
-	if newCollisionBox || newLightBox || newNpc || newParticleSource {
-		height := float32(100)
-		width := float32(100)
+	if levelInfo {
+		s.editMenuLevelInfoMode = !s.editMenuLevelInfoMode
+		s.levelInfoEditField = noLevelInfoEditField
+	}
+
+	if s.editMenuLevelInfoMode {
+		s.drawLevelInfoHub()
+	}
 
-		topLeft := s.camera.Target
-		bottomLeft := rl.Vector2{topLeft.X, topLeft.Y + height}
+	if levelBounds && !s.hasLevelBounds() {
+		level := s.levelFromEditor()
+		bounds := models.NewLevelBounds(level.ContentBounds())
+		s.worldContainer.AddObject(bounds)
+		s.pushAddObject(bounds)
+	}
 
-		topRight := rl.Vector2{topLeft.X + width, topLeft.Y}
-		bottomRight := rl.Vector2{topLeft.X + width, topLeft.Y + height}
+	if exportPack {
+		s.saveEditor()
+		archivePath, err := repository.ExportLevelPack(s.level.Name)
+		if err != nil {
+			s.showEditorMessage("EXPORT FAILED: " + err.Error())
+		} else {
+			s.showEditorMessage("EXPORTED " + archivePath)
+		}
+	}
 
+	if newCollisionBox || newLightBox || newNpc || newParticleSource || newTrigger || newCheckpoint {
 		var newObject models.Object
 
-		baseEditorItem := models.NewBaseEditorItem([2]collision.Polygon{
-			{
-				Points: [3]rl.Vector2{
-					topLeft, topRight, bottomRight,
-				},
-			},
-			{
-				Points: [3]rl.Vector2{
-					topLeft, bottomLeft, bottomRight,
-				},
-			},
-		})
+		baseEditorItem := models.NewBaseEditorItem(models.RectanglePolygons(s.camera.Target, 100, 100))
 
 		if newCollisionBox {
 			newObject = &models.CollisionHitbox{
@@ -362,6 +581,22 @@ func (s *EditScene) drawMainHub() {
 			}
 		}
 
+		if newTrigger {
+			newObject = &models.Trigger{
+				CollisionHitbox: models.CollisionHitbox{
+					BaseEditorItem: baseEditorItem,
+				},
+			}
+		}
+
+		if newCheckpoint {
+			newObject = &models.Checkpoint{
+				CollisionHitbox: models.CollisionHitbox{
+					BaseEditorItem: baseEditorItem,
+				},
+			}
+		}
+
 		if newLightBox {
 			newObject = &models.Light{
 				BaseEditorItem: baseEditorItem,
@@ -385,10 +620,151 @@ func (s *EditScene) drawMainHub() {
 		}
 
 		s.worldContainer.AddObject(newObject)
+		s.pushAddObject(newObject)
 	}
 }
 
-func (s *EditScene) reactOnEditorItemSelection(container *container.ObjectResourceContainer, item *models.BaseEditorItem, bc *models.Counter) {
+func (s EditScene) hasLevelBounds() bool {
+	found := false
+	s.worldContainer.ForEachObject(func(obj models.Object) {
+		_, ok := obj.(*models.LevelBounds)
+		if ok {
+			found = true
+		}
+	})
+	return found
+}
+
+func (s *EditScene) drawLevelInfoHub() {
+	bc := models.NewCounter()
+
+	s.levelInfoTextBox(&bc, 0, "TITLE", &s.level.Title)
+	s.levelInfoTextBox(&bc, 1, "AUTHOR", &s.level.Author)
+	s.levelInfoTextBox(&bc, 2, "DESCRIPTION", &s.level.Description)
+	s.levelInfoTextBox(&bc, 3, "THUMBNAIL", &s.level.Thumbnail)
+
+	order := int32(s.level.Order)
+	s.levelInfoSpinner(&bc, 4, "ORDER", &order, 0, 1000)
+	s.level.Order = int(order)
+
+	s.levelInfoSecondsBox(&bc, 5, "PAR TIME(S)", &s.level.ParTime)
+	s.levelInfoSecondsBox(&bc, 6, "REWIND(S)", &s.level.RewindBudget)
+
+	_, lockedRect := s.secondColumnRow(&bc)
+	lockedRect.Width = lockedRect.Height
+	s.level.Locked = rg.CheckBox(lockedRect, "LOCKED", s.level.Locked)
+
+	_, dashRect := s.secondColumnRow(&bc)
+	dashRect.Width = dashRect.Height
+	if rg.CheckBox(dashRect, "DASH ABILITY", s.level.Dash) != s.level.Dash {
+		command := &dashCommand{level: &s.level}
+		command.redo(s.worldContainer)
+		s.history.push(command)
+	}
+}
+
+func (s *EditScene) levelInfoTextBox(bc *models.Counter, field int, label string, text *string) {
+	labelRect, fieldRect := s.secondColumnRow(bc)
+	rg.Label(labelRect, label)
+	if rg.TextBox(fieldRect, text, maxTextSize, s.levelInfoEditField == field) {
+		s.toggleLevelInfoEditField(field)
+	}
+}
+
+func (s *EditScene) levelInfoSpinner(bc *models.Counter, field int, label string, value *int32, min, max int) {
+	labelRect, fieldRect := s.secondColumnRow(bc)
+	rg.Label(labelRect, label)
+	if rg.Spinner(fieldRect, "", value, min, max, s.levelInfoEditField == field) {
+		s.toggleLevelInfoEditField(field)
+	}
+}
+
+// levelInfoSecondsBox edits fractional seconds, value follows text while it is a valid number
+func (s *EditScene) levelInfoSecondsBox(bc *models.Counter, field int, label string, value *float32) {
+	labelRect, fieldRect := s.secondColumnRow(bc)
+	rg.Label(labelRect, label)
+	if floatTextBox(fieldRect, value, 0, 3600, s.levelInfoEditField == field, &s.levelInfoDraft) {
+		s.toggleLevelInfoEditField(field)
+	}
+}
+
+// floatTextBox edits fractional number, value follows text while it is a valid number in range.
+// Text being typed is kept in draft, returns true when editing should be toggled
+func floatTextBox(rect rl.Rectangle, value *float32, min, max float32, editing bool, draft *string) bool {
+	text := strconv.FormatFloat(float64(*value), 'f', -1, 32)
+	if editing {
+		text = *draft
+	}
+
+	toggle := rg.TextBox(rect, &text, maxTextSize, editing)
+
+	if editing {
+		*draft = text
+		number, err := strconv.ParseFloat(strings.TrimSpace(text), 32)
+		if err == nil && float32(number) >= min && float32(number) <= max {
+			*value = float32(number)
+		}
+	} else if toggle { // editing starts with shown value
+		*draft = text
+	}
+	return toggle
+}
+
+func (s *EditScene) toggleLevelInfoEditField(field int) {
+	if s.levelInfoEditField == field {
+		s.levelInfoEditField = noLevelInfoEditField
+	} else {
+		s.levelInfoEditField = field
+	}
+}
+
+func (s *EditScene) itemFloatBox(bc *models.Counter, field int, label string, value *float32, min, max float32) {
+	labelRect, fieldRect := s.secondColumnRow(bc)
+	rg.Label(labelRect, label)
+	if floatTextBox(fieldRect, value, min, max, s.itemEditField == field, &s.itemDraft) {
+		s.toggleItemEditField(field)
+	}
+}
+
+func (s *EditScene) itemSpinner(bc *models.Counter, field int, label string, value *int32, min, max int) {
+	labelRect, fieldRect := s.secondColumnRow(bc)
+	rg.Label(labelRect, label)
+	if rg.Spinner(fieldRect, "", value, min, max, s.itemEditField == field) {
+		s.toggleItemEditField(field)
+	}
+}
+
+func (s *EditScene) itemTextBox(bc *models.Counter, field int, label string, text *string) {
+	labelRect, fieldRect := s.secondColumnRow(bc)
+	rg.Label(labelRect, label)
+	if rg.TextBox(fieldRect, text, maxTextSize, s.itemEditField == field) {
+		s.toggleItemEditField(field)
+	}
+}
+
+func (s *EditScene) toggleItemEditField(field int) {
+	if s.itemEditField == field {
+		s.itemEditField = noItemEditField
+	} else {
+		s.itemEditField = field
+	}
+}
+
+func (s EditScene) isTextEditing() bool {
+	return (s.editMenuLevelInfoMode && s.levelInfoEditField != noLevelInfoEditField) ||
+		s.itemEditField != noItemEditField
+}
+
+// level info and platform settings are drawn as a second column right to main hub buttons
+func (s EditScene) secondColumnRow(bc *models.Counter) (rl.Rectangle, rl.Rectangle) {
+	posX := float32(editorControlMarginLeft) + editorControlRectWidth + 50
+	posY := s.itemPosY(bc)
+	labelRect := rl.NewRectangle(posX, posY, editorControlRectWidth, editorControlRectHeight)
+	fieldRect := rl.NewRectangle(posX+editorControlRectWidth, posY, editorControlRectWidth*2, editorControlRectHeight)
+	return labelRect, fieldRect
+}
+
+func (s *EditScene) reactOnEditorItemSelection(container *container.ObjectResourceContainer, obj models.Object, item *models.BaseEditorItem, bc *models.Counter) {
 
 	changePos := rg.Button(s.controlRect(bc), "CHANGE POS")
 	resize := rg.Button(s.controlRect(bc), "RESIZE")
@@ -399,14 +775,16 @@ func (s *EditScene) reactOnEditorItemSelection(container *container.ObjectResour
 	moveUpper := rg.Button(s.controlRect(bc), "MOVE UPPER")
 	moveDown := rg.Button(s.controlRect(bc), "MOVE DOWN")
 
-	if moveUpper {
-		container.MoveUp(item)
-		item.DrawIndex--
-	}
-
-	if moveDown {
-		container.MoveDown(item)
-		item.DrawIndex++
+	if moveUpper || moveDown {
+		command := &moveOrderCommand{
+			obj:       obj,
+			item:      item,
+			up:        moveUpper,
+			fromIndex: container.IndexOf(obj),
+		}
+		command.redo(container)
+		command.toIndex = container.IndexOf(obj)
+		s.history.push(command)
 	}
 
 	if unselect {
@@ -430,7 +808,13 @@ func (s *EditScene) reactOnEditorItemSelection(container *container.ObjectResour
 	}
 
 	if deleteItem {
-		s.worldContainer.RemoveObject(item)
+		s.history.endEdit()
+		s.history.push(&removeObjectCommand{
+			obj:   obj,
+			index: container.IndexOf(obj),
+		})
+		container.RemoveObject(obj)
+		item.ExternalUnselect = true
 	}
 
 }
@@ -460,8 +844,92 @@ func (s *EditScene) reactOnImageEditorSelection(container *container.ObjectResou
 		imageReplica := image.Replicate(uuid.NewString(), topLeft.X-100, topLeft.Y-100)
 		imageReplica.Load()
 		container.AddObjectResource(imageReplica)
+		s.history.push(&addObjectCommand{
+			obj:   imageReplica,
+			index: container.IndexOf(imageReplica),
+		})
+	}
+
+}
+
+func (s *EditScene) reactOnHitboxEditorSelection(hitbox *models.CollisionHitbox, bc *models.Counter) {
+
+	editVertices := rg.Button(s.controlRect(bc), "EDIT VERTICES")
+
+	oneWayText := "ONE WAY: OFF"
+	if hitbox.OneWay {
+		oneWayText = "ONE WAY: ON"
+	}
+	oneWay := rg.Button(s.controlRect(bc), oneWayText)
+
+	s.drawLayerButton(hitbox, collision.LAYER_WORLD, bc)
+
+	material := collision.DEFAULT_MATERIAL
+	if hitbox.Material != nil {
+		material = *hitbox.Material
+	}
+	nextMaterial := rg.Button(s.controlRect(bc), "MATERIAL: "+strings.ToUpper(material.Name))
+
+	if editVertices && s.vertexEditHitbox == nil && s.waypointHitbox == nil {
+		s.startVertexEditing(hitbox)
+	}
+
+	if oneWay {
+		command := &oneWayCommand{hitbox: hitbox}
+		command.redo(s.worldContainer)
+		s.history.push(command)
+	}
+
+	if nextMaterial {
+		command := &materialCommand{
+			hitbox: hitbox,
+			before: hitbox.Material,
+		}
+		if next := collision.NextMaterial(material); next.Name != collision.DEFAULT_MATERIAL.Name {
+			command.after = &next
+		}
+		command.redo(s.worldContainer)
+		s.history.push(command)
+	}
+
+	s.drawPlatformHub(hitbox, bc)
+
+}
+
+// drawLayerButton draws toggle per collision layer, hitbox may belong to several layers.
+// Layers equal to fallback are stored as zero so hitbox keeps following its type default
+func (s *EditScene) drawLayerButton(hitbox *models.CollisionHitbox, fallback collision.Layer, bc *models.Counter) {
+	layer := hitbox.CollisionLayer(fallback)
+	next := layer
+
+	var rect rl.Rectangle
+	toggleWidth := editorControlRectWidth / layerTogglesPerRow
+	for i, _ := range collision.LAYERS {
+		if i%layerTogglesPerRow == 0 {
+			rect = s.controlRect(bc)
+			rect.Width = toggleWidth
+		} else {
+			rect.X += toggleWidth
+		}
+		l := collision.LAYERS[i]
+		if rg.Toggle(rect, strings.ToUpper(l.String()), layer&l != 0) != (layer&l != 0) {
+			next ^= l
+		}
 	}
 
+	if next == layer {
+		return
+	}
+	if next == fallback {
+		next = 0
+	}
+	command := &layerCommand{
+		hitbox: hitbox,
+		before: hitbox.Layer,
+		after:  next,
+	}
+	command.redo(s.worldContainer)
+	s.history.push(command)
 }
 
 func (s *EditScene) drawHubForItem(editorItem models.EditorItem) {
@@ -470,34 +938,57 @@ func (s *EditScene) drawHubForItem(editorItem models.EditorItem) {
 
 	img, isImg := editorItem.(*models.Image)
 	if isImg {
-		s.reactOnEditorItemSelection(s.worldContainer, &img.BaseEditorItem, &buttonCounter)
+		s.reactOnEditorItemSelection(s.worldContainer, img, &img.BaseEditorItem, &buttonCounter)
 		s.reactOnImageEditorSelection(s.worldContainer, img, &buttonCounter)
 	}
 
 	collisionHitbox, isHitbox := editorItem.(*models.CollisionHitbox)
 	if isHitbox {
-		s.reactOnEditorItemSelection(s.worldContainer, &collisionHitbox.BaseEditorItem, &buttonCounter)
+		s.reactOnEditorItemSelection(s.worldContainer, collisionHitbox, &collisionHitbox.BaseEditorItem, &buttonCounter)
+		s.reactOnHitboxEditorSelection(collisionHitbox, &buttonCounter)
 	}
 
 	light, isLight := editorItem.(*models.Light)
 	if isLight {
-		s.reactOnEditorItemSelection(s.worldContainer, &light.BaseEditorItem, &buttonCounter)
+		s.reactOnEditorItemSelection(s.worldContainer, light, &light.BaseEditorItem, &buttonCounter)
 	}
 
 	npc, isNpc := editorItem.(*models.Npc)
 	if isNpc {
-		s.reactOnEditorItemSelection(s.worldContainer, &npc.BaseEditorItem, &buttonCounter)
+		s.reactOnEditorItemSelection(s.worldContainer, npc, &npc.BaseEditorItem, &buttonCounter)
+		s.drawLayerButton(&npc.CollisionHitbox, collision.LAYER_NPC, &buttonCounter)
 	}
 
 	particleSource, isParticleSource := editorItem.(*models.ParticleSource)
 	if isParticleSource {
-		s.reactOnEditorItemSelection(s.worldContainer, &particleSource.BaseEditorItem, &buttonCounter)
+		s.reactOnEditorItemSelection(s.worldContainer, particleSource, &particleSource.BaseEditorItem, &buttonCounter)
+	}
+
+	trigger, isTrigger := editorItem.(*models.Trigger)
+	if isTrigger {
+		s.reactOnEditorItemSelection(s.worldContainer, trigger, &trigger.BaseEditorItem, &buttonCounter)
+		s.drawLayerButton(&trigger.CollisionHitbox, collision.LAYER_TRIGGER, &buttonCounter)
+		s.drawTriggerHub(trigger, &buttonCounter)
+	}
+
+	checkpoint, isCheckpoint := editorItem.(*models.Checkpoint)
+	if isCheckpoint {
+		s.reactOnEditorItemSelection(s.worldContainer, checkpoint, &checkpoint.BaseEditorItem, &buttonCounter)
+	}
+
+	levelBounds, isLevelBounds := editorItem.(*models.LevelBounds)
+	if isLevelBounds {
+		s.reactOnEditorItemSelection(s.worldContainer, levelBounds, &levelBounds.BaseEditorItem, &buttonCounter)
 	}
 
 }
 
 func (s *EditScene) processInputs() {
 
+	if s.isTextEditing() {
+		return // keyboard belongs to text box
+	}
+
 	mousePos := rl.GetMousePosition()
 
 	updateMouse := false
@@ -537,21 +1028,23 @@ func (s *EditScene) processInputs() {
 		s.editCameraSpeed--
 	}
 
-	if rl.IsKeyDown(rl.KeyF10) {
+	if rl.IsKeyPressed(rl.KeyF10) {
 		s.saveEditor()
-		s.worldContainer.AddObject(
-			models.NewText(int32(s.camera.Target.X-s.camera.Offset.X+WIDTH/2), int32(s.camera.Target.Y-s.camera.Offset.Y+HEIGHT/2)).
-				SetData("DATA SAVED").
-				SetFontSize(60).
-				SetColor(rl.Red).
-				WithExpire(3, func(t *models.Text) {
-					s.worldContainer.RemoveObject(t)
-				}),
-		)
+		s.showEditorMessage("DATA SAVED")
 	}
 
 	hasAnySelected, _ := s.hasAnySelectedGameObjectEditorItem()
 
+	ctrlDown := rl.IsKeyDown(rl.KeyLeftControl) || rl.IsKeyDown(rl.KeyRightControl)
+	if ctrlDown && !hasAnySelected {
+		if rl.IsKeyPressed(rl.KeyZ) {
+			s.history.undo(s.worldContainer)
+		}
+		if rl.IsKeyPressed(rl.KeyY) {
+			s.history.redo(s.worldContainer)
+		}
+	}
+
 	if hasAnySelected {
 
 		if !s.editorHubEnabled {
@@ -0,0 +1,113 @@
+package scene
+
+import (
+	"ahasuerus/models"
+	"fmt"
+
+	rg "github.com/gen2brain/raylib-go/raygui"
+)
+
+const (
+	triggerValueEditField    = 2
+	triggerReverseEditField  = 3
+	triggerOffsetXEditField  = 4
+	triggerOffsetYEditField  = 5
+	triggerDurationEditField = 6
+	triggerFlagEditField     = 7
+)
+
+// drawTriggerHub composes new action in second column and lists trigger actions below it
+func (s *EditScene) drawTriggerHub(trigger *models.Trigger, bc *models.Counter) {
+	before := triggerStateOf(trigger)
+
+	onceText := "ONCE: OFF"
+	if trigger.Once {
+		onceText = "ONCE: ON"
+	}
+	once := rg.Button(s.controlRect(bc), onceText)
+	addAction := rg.Button(s.controlRect(bc), "ADD ACTION")
+
+	column := models.NewCounter()
+
+	s.itemTextBox(&column, triggerFlagEditField, "IF FLAG", &trigger.Flag)
+
+	draft := &s.triggerDraft
+	if draft.Event == "" {
+		draft.Event = models.TriggerEnter
+		draft.Type = models.ShowTextAction
+	}
+
+	labelRect, fieldRect := s.secondColumnRow(&column)
+	rg.Label(labelRect, "EVENT")
+	if rg.Button(fieldRect, string(draft.Event)) {
+		draft.Event = nextTriggerEvent(draft.Event)
+	}
+
+	labelRect, fieldRect = s.secondColumnRow(&column)
+	rg.Label(labelRect, "ACTION")
+	if rg.Button(fieldRect, string(draft.Type)) {
+		draft.Type = nextTriggerActionType(draft.Type)
+	}
+
+	s.itemTextBox(&column, triggerValueEditField, "VALUE", &draft.Value)
+	if draft.Type == models.StartMusicAction {
+		s.itemTextBox(&column, triggerReverseEditField, "REVERSE", &draft.Reverse)
+	}
+
+	s.itemFloatBox(&column, triggerOffsetXEditField, "OFFSET X", &draft.Offset.X, -10000, 10000)
+	s.itemFloatBox(&column, triggerOffsetYEditField, "OFFSET Y", &draft.Offset.Y, -10000, 10000)
+	s.itemFloatBox(&column, triggerDurationEditField, "DURATION(S)", &draft.Duration, 0, 600)
+
+	deleteIndex := -1
+	for i, _ := range trigger.Actions {
+		labelRect, fieldRect := s.secondColumnRow(&column)
+		rg.Label(labelRect, trigger.Actions[i].String())
+		fieldRect.Width = fieldRect.Width / 2
+		if rg.Button(fieldRect, "DELETE") {
+			deleteIndex = i
+		}
+	}
+
+	if once {
+		trigger.Once = !trigger.Once
+	}
+
+	if addAction && !draft.IsAllowed() {
+		fmt.Printf("WARN: %s action is not allowed on %s event\n", draft.Type, draft.Event)
+	} else if addAction {
+		trigger.Actions = append(trigger.CopyActions(), *draft)
+		s.itemEditField = noItemEditField
+	}
+
+	if deleteIndex >= 0 {
+		actions := trigger.CopyActions()
+		trigger.Actions = append(actions[:deleteIndex], actions[deleteIndex+1:]...)
+	}
+
+	after := triggerStateOf(trigger)
+	if !before.equals(after) {
+		s.history.pushItemCommand(&triggerCommand{
+			trigger: trigger,
+			before:  before,
+			after:   after,
+		})
+	}
+}
+
+func nextTriggerEvent(current models.TriggerEvent) models.TriggerEvent {
+	for i, _ := range models.TRIGGER_EVENTS {
+		if models.TRIGGER_EVENTS[i] == current {
+			return models.TRIGGER_EVENTS[(i+1)%len(models.TRIGGER_EVENTS)]
+		}
+	}
+	return models.TRIGGER_EVENTS[0]
+}
+
+func nextTriggerActionType(current models.TriggerActionType) models.TriggerActionType {
+	for i, _ := range models.TRIGGER_ACTION_TYPES {
+		if models.TRIGGER_ACTION_TYPES[i] == current {
+			return models.TRIGGER_ACTION_TYPES[(i+1)%len(models.TRIGGER_ACTION_TYPES)]
+		}
+	}
+	return models.TRIGGER_ACTION_TYPES[0]
+}
@@ -0,0 +1,165 @@
+package scene
+
+import (
+	"ahasuerus/controls"
+	"ahasuerus/models"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	VERTEX_HANDLE_RADIUS = 15
+	noVertex             = -1
+)
+
+func (s *EditScene) startVertexEditing(hitbox *models.CollisionHitbox) {
+	s.history.endEdit()
+
+	before := hitboxShapeState(hitbox)
+	hitbox.BakeRotation()
+	after := hitboxShapeState(hitbox)
+	if !before.equals(after) {
+		s.history.pushItemCommand(&shapeCommand{
+			hitbox: hitbox,
+			before: before,
+			after:  after,
+		})
+	}
+
+	s.vertexEditHitbox = hitbox
+	s.vertexDragIndex = noVertex
+
+	controls.DisableCursor(24)
+	controls.SetMousePosition(int(hitbox.TopLeft().X), int(hitbox.TopLeft().Y), 25)
+}
+
+func (s *EditScene) stopVertexEditing() {
+	if s.vertexDragIndex != noVertex {
+		s.finishVertexChange()
+	}
+	s.vertexEditHitbox = nil
+	s.vertexDragIndex = noVertex
+	if s.editorHubEnabled {
+		controls.EnableCursor(37)
+	}
+}
+
+// processVertexEditing drags vertices with mouse, adds vertex on nearest edge (A),
+// deletes hovered vertex (DELETE) and finishes on ENTER
+func (s *EditScene) processVertexEditing() {
+	hitbox := s.vertexEditHitbox
+	if !hitbox.EditSelected {
+		s.stopVertexEditing()
+		return
+	}
+
+	mousePos := rl.GetMousePosition()
+	points := hitbox.ShapePoints()
+	hovered := nearestVertex(points, mousePos)
+
+	if s.vertexDragIndex != noVertex {
+		points[s.vertexDragIndex] = mousePos
+		hitbox.SetShapePoints(points)
+		if rl.IsMouseButtonReleased(rl.MouseLeftButton) {
+			s.finishVertexChange()
+		}
+	} else if rl.IsMouseButtonPressed(rl.MouseLeftButton) && hovered != noVertex {
+		s.vertexDragIndex = hovered
+		s.vertexBefore = hitboxShapeState(hitbox)
+	}
+
+	if s.vertexDragIndex == noVertex {
+		if rl.IsKeyPressed(rl.KeyA) {
+			s.vertexBefore = hitboxShapeState(hitbox)
+			edge := nearestEdge(points, mousePos)
+			points = append(points[:edge+1], append([]rl.Vector2{mousePos}, points[edge+1:]...)...)
+			hitbox.SetShapePoints(points)
+			s.finishVertexChange()
+		}
+
+		if rl.IsKeyPressed(rl.KeyDelete) && hovered != noVertex && len(points) > 3 {
+			s.vertexBefore = hitboxShapeState(hitbox)
+			points = append(points[:hovered], points[hovered+1:]...)
+			hitbox.SetShapePoints(points)
+			s.finishVertexChange()
+		}
+
+		if rl.IsKeyPressed(rl.KeyEnter) {
+			s.stopVertexEditing()
+			return
+		}
+	}
+
+	s.drawVertices(hitbox.ShapePoints(), hovered)
+}
+
+func (s *EditScene) finishVertexChange() {
+	after := hitboxShapeState(s.vertexEditHitbox)
+	if !s.vertexBefore.equals(after) {
+		s.history.pushItemCommand(&shapeCommand{
+			hitbox: s.vertexEditHitbox,
+			before: s.vertexBefore,
+			after:  after,
+		})
+	}
+	s.vertexDragIndex = noVertex
+}
+
+func (s EditScene) drawVertices(points []rl.Vector2, hovered int) {
+	for i, _ := range points {
+		rl.DrawLineEx(points[i], points[(i+1)%len(points)], 3, rl.Orange)
+	}
+	for i, _ := range points {
+		color := rl.Red
+		if i == hovered || i == s.vertexDragIndex {
+			color = rl.Yellow
+		}
+		rl.DrawCircleV(points[i], VERTEX_HANDLE_RADIUS, color)
+	}
+
+	mousePos := rl.GetMousePosition()
+	rl.DrawCircle(int32(mousePos.X), int32(mousePos.Y), 10, rl.Red)
+
+	topLeft := s.vertexEditHitbox.TopLeft()
+	rl.DrawText("\nDrag vertex [MOUSE], add [A], delete [DELETE], done [ENTER]", int32(topLeft.X), int32(topLeft.Y+40), 40, rl.Red)
+}
+
+func nearestVertex(points []rl.Vector2, pos rl.Vector2) int {
+	nearest := noVertex
+	nearestDistance := float32(VERTEX_HANDLE_RADIUS)
+	for i, _ := range points {
+		distance := rl.Vector2Distance(points[i], pos)
+		if distance <= nearestDistance {
+			nearest = i
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
+
+// nearestEdge returns index of first vertex of edge closest to pos
+func nearestEdge(points []rl.Vector2, pos rl.Vector2) int {
+	nearest := 0
+	nearestDistance := float32(-1)
+	for i, _ := range points {
+		a := points[i]
+		b := points[(i+1)%len(points)]
+		distance := rl.Vector2Distance(pos, closestPointOnSegment(a, b, pos))
+		if nearestDistance < 0 || distance < nearestDistance {
+			nearest = i
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
+
+func closestPointOnSegment(a, b, pos rl.Vector2) rl.Vector2 {
+	ab := rl.Vector2Subtract(b, a)
+	lengthSqr := rl.Vector2DotProduct(ab, ab)
+	if lengthSqr == 0 {
+		return a
+	}
+	t := rl.Vector2DotProduct(rl.Vector2Subtract(pos, a), ab) / lengthSqr
+	t = rl.Clamp(t, 0, 1)
+	return rl.Vector2Add(a, rl.Vector2Scale(ab, t))
+}
@@ -0,0 +1,86 @@
+package scene
+
+import (
+	"ahasuerus/models"
+	"ahasuerus/repository"
+)
+
+// restartFromCheckpoint asks game scene to respawn at checkpoint on next run
+var restartFromCheckpoint bool
+
+func (s *GameScene) activateCheckpoint(checkpoint *models.Checkpoint) {
+	state := &repository.CheckpointState{
+		Id:             checkpoint.Id,
+		PlayerPos:      checkpoint.SpawnPos(),
+		Flags:          models.GetFlags(),
+		CollectedItems: models.GetCollectedItems(),
+		Dialogues:      make(map[string]uint),
+		SpentTriggers:  make([]string, 0),
+	}
+	for i, _ := range s.npcs {
+		state.Dialogues[s.npcs[i].Id] = s.npcs[i].Dialogues.CurrentInteraction
+	}
+	for i, _ := range s.triggers {
+		if s.triggers[i].IsSpent() {
+			state.SpentTriggers = append(state.SpentTriggers, s.triggers[i].Id)
+		}
+	}
+	s.setCheckpoint(state)
+
+	if currentSave != nil {
+		currentSave.Checkpoint = checkpoint.Id
+		currentSave.CheckpointState = state
+	}
+}
+
+func (s *GameScene) setCheckpoint(state *repository.CheckpointState) {
+	for i, _ := range s.checkpoints {
+		s.checkpoints[i].Active = s.checkpoints[i].Id == state.Id
+	}
+	s.checkpoint = state
+}
+
+// respawnAtCheckpoint restores level state recorded by last checkpoint
+func (s *GameScene) respawnAtCheckpoint() {
+	state := s.checkpoint
+	if state == nil {
+		return
+	}
+
+	models.ResetFlags(state.Flags)
+	models.ResetCollectedItems(state.CollectedItems)
+	for i, _ := range s.npcs {
+		if interaction, ok := state.Dialogues[s.npcs[i].Id]; ok {
+			s.npcs[i].Dialogues.CurrentInteraction = interaction
+		}
+	}
+	s.restoreSpentTriggers()
+
+	s.player.Respawn(state.PlayerPos)
+	s.camera.Target = s.clampCameraTarget(state.PlayerPos)
+}
+
+func (s *GameScene) restoreSpentTriggers() {
+	for i, _ := range s.triggers {
+		s.triggers[i].ResetState(s.checkpoint.IsTriggerSpent(s.triggers[i].Id))
+	}
+}
+
+// restoreSavedCheckpoint restores checkpoint stored in save game of this level,
+// older saves without recorded state snapshot level as loaded
+func (s *GameScene) restoreSavedCheckpoint() {
+	if currentSave == nil || currentSave.Level != s.level.Name || currentSave.Checkpoint == "" {
+		return
+	}
+	for i, _ := range s.checkpoints {
+		if s.checkpoints[i].Id != currentSave.Checkpoint {
+			continue
+		}
+		if currentSave.CheckpointState != nil && currentSave.CheckpointState.Id == currentSave.Checkpoint {
+			s.setCheckpoint(currentSave.CheckpointState)
+		} else {
+			s.activateCheckpoint(s.checkpoints[i])
+		}
+		return
+	}
+}
@@ -0,0 +1,52 @@
+package scene
+
+import (
+	"ahasuerus/models"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	KILL_PLANE_MARGIN = 300 // player dies this far below level bounds
+
+	healthMarginLeft = 30
+	healthMarginTop  = 80
+	healthSize       = 30
+	healthSpacing    = 10
+)
+
+func (s GameScene) drawHealth() {
+	for i := int32(0); i < models.PLAYER_MAX_HEALTH; i++ {
+		rect := rl.NewRectangle(healthMarginLeft+float32(i*(healthSize+healthSpacing)), healthMarginTop, healthSize, healthSize)
+		if i < s.player.GetHealth() {
+			rl.DrawRectangleRec(rect, rl.Red)
+		} else {
+			rl.DrawRectangleLinesEx(rect, 3, rl.Red)
+		}
+	}
+}
+
+// drawDeathOverlay offers rewind to before death or level restart
+func (s GameScene) drawDeathOverlay() {
+	rl.DrawRectangle(0, 0, int32(WIDTH), int32(HEIGHT), rl.Fade(rl.Black, 0.5))
+	models.DrawSdfText("YOU DIED", rl.NewVector2(WIDTH/10, HEIGHT/3), 100, rl.Red)
+	models.DrawSdfText("hold SHIFT to rewind to before death", rl.NewVector2(WIDTH/10, HEIGHT/3+120), 50, rl.White)
+	restartText := "press ENTER to restart level"
+	if s.checkpoint != nil {
+		restartText = "press ENTER to restart from checkpoint"
+	}
+	models.DrawSdfText(restartText, rl.NewVector2(WIDTH/10, HEIGHT/3+180), 50, rl.Gray)
+}
+
+// restart reloads level from its start, progress of failed attempt is dropped
+func (s *GameScene) restart() models.Scene {
+	if currentSave != nil {
+		currentSave.PlayerPos = rl.Vector2{}
+		currentSave.ClearCheckpoint()
+		models.ResetCollectedItems(currentSave.CollectedItems)
+		models.ResetFlags(currentSave.Flags)
+	}
+	id := SceneId(s.level.Name)
+	UnloadScene(id)
+	return GetScene(id)
+}
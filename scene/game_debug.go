@@ -0,0 +1,40 @@
+package scene
+
+import (
+	"ahasuerus/collision"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const DEBUG_NORMAL_LENGTH = 30
+
+// drawCollisionDebug highlights hitboxes under mouse and casts ray from player to mouse
+func (s *GameScene) drawCollisionDebug() {
+	mouse := rl.GetScreenToWorld2D(rl.GetMousePosition(), *s.camera)
+	processor := s.collisions
+
+	hovered := processor.QueryPoint(mouse, collision.LAYER_ALL)
+	for i, _ := range hovered {
+		drawHitboxOutline(hovered[i], rl.Magenta)
+	}
+
+	bounds := s.player.GetHitbox().Bounds()
+	from := rl.NewVector2(bounds.X+bounds.Width/2, bounds.Y+bounds.Height/2)
+
+	hit, ok := processor.Raycast(from, mouse, collision.LAYER_ALL&^collision.LAYER_PLAYER)
+	if !ok {
+		rl.DrawLineV(from, mouse, rl.Green)
+		return
+	}
+
+	rl.DrawLineV(from, hit.Point, rl.Red)
+	rl.DrawCircleV(hit.Point, 4, rl.Red)
+	rl.DrawLineV(hit.Point, rl.Vector2Add(hit.Point, rl.Vector2Scale(hit.Normal, DEBUG_NORMAL_LENGTH)), rl.Yellow)
+}
+
+func drawHitboxOutline(hitbox *collision.Hitbox, color rl.Color) {
+	for i, _ := range hitbox.Polygons {
+		points := hitbox.Polygons[i].Points
+		rl.DrawTriangleLines(points[0], points[1], points[2], color)
+	}
+}
@@ -0,0 +1,51 @@
+package scene
+
+import (
+	"ahasuerus/models"
+	"fmt"
+
+	rg "github.com/gen2brain/raylib-go/raygui"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	physicsPanelMarginLeft = 250
+	physicsPanelStartPosY  = 80
+	physicsPanelRowWidth   = float32(400)
+	physicsPanelRowHeight  = float32(40)
+)
+
+// drawPhysicsPanel tunes player physics while playing, SAVE writes tuned values to default profile
+// except fields overridden by level, those stay level specific
+func (s *GameScene) drawPhysicsPanel() {
+	bc := models.NewCounter()
+
+	for i, _ := range models.PHYSICS_FIELDS {
+		field := models.PHYSICS_FIELDS[i]
+		value := field.Value(s.player.Physics)
+		text := fmt.Sprintf("%.2f", value)
+		if field.Whole {
+			text = fmt.Sprintf("%d", int32(value))
+		}
+		field.SetValue(&s.player.Physics, rg.SliderBar(s.physicsPanelRect(&bc), field.Label, text, value, field.Min, field.Max))
+	}
+
+	if rg.Button(s.physicsPanelRect(&bc), "SAVE PROFILE") {
+		base := s.level.Physics.Restore(s.player.Physics, s.basePhysics)
+		err := models.SavePhysicsProfile(models.PHYSICS_PROFILE_PATH, base)
+		if err != nil {
+			fmt.Println("WARN: Physics profile not saved:", err)
+		} else {
+			s.basePhysics = base
+		}
+	}
+
+	if rg.Button(s.physicsPanelRect(&bc), "RESET") {
+		s.player.Physics = s.level.Physics.Apply(s.basePhysics)
+	}
+}
+
+func (s GameScene) physicsPanelRect(bc *models.Counter) rl.Rectangle {
+	posY := float32(physicsPanelStartPosY) + physicsPanelRowHeight*float32(bc.GetAndIncrement())
+	return rl.NewRectangle(physicsPanelMarginLeft, posY, physicsPanelRowWidth, physicsPanelRowHeight)
+}
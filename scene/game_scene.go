@@ -7,6 +7,7 @@ import (
 	"ahasuerus/repository"
 	"ahasuerus/resources"
 	"fmt"
+	"math"
 
 	rg "github.com/gen2brain/raylib-go/raygui"
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -15,14 +16,29 @@ import (
 type GameScene struct {
 	worldContainer *container.ObjectResourceContainer
 	camera         *rl.Camera2D
+	collisions     *collision.CollisionDetector
 	player         *models.Player
+	npcs           []*models.Npc
+	triggers       []*models.Trigger
+	checkpoints    []*models.Checkpoint
+	checkpoint     *repository.CheckpointState
 
 	level repository.Level
 
 	onScreenQueue chan models.Object
 
-	paused bool
-	size rl.Vector2
+	paused       bool
+	physicsPanel bool
+	basePhysics  models.PhysicsProfile // default profile without level override, tuned by physics panel
+	bounds       rl.Rectangle
+
+	levelTime  float32
+	rewindTime float32
+
+	music           *models.MusicStream
+	triggerText     *models.Text
+	cameraFocus     rl.Vector2
+	cameraFocusTime float32
 
 	screenScale float32
 }
@@ -31,24 +47,42 @@ func NewGameScene(sceneName string) *GameScene {
 	scene := GameScene{
 		worldContainer: container.NewObjectResourceContainer(),
 		onScreenQueue:  make(chan models.Object, 2),
+		collisions:     &collision.CollisionDetector{},
 	}
 
 	scene.level = repository.GetLevel(sceneName)
 
-	scene.size = scene.level.Size()
-	scene.screenScale = HEIGHT/scene.size.Y
+	if currentSave != nil {
+		currentSave.UnlockLevel(sceneName)
+	}
+
+	scene.bounds = scene.level.GetBounds()
+	scene.screenScale = HEIGHT/scene.bounds.Height
 
 	camera := rl.NewCamera2D(
 		rl.NewVector2(WIDTH/2, HEIGHT/2),
-		rl.NewVector2(WIDTH/2, scene.size.Y/2),
+		rl.NewVector2(scene.bounds.X+WIDTH/2, scene.bounds.Y+scene.bounds.Height/2),
 		0, scene.screenScale)
 
 	scene.camera = &camera
 
-	scene.player = models.NewPlayer(float32(scene.level.PlayerPos.X), float32(scene.level.PlayerPos.Y)).WithShader(resources.GameShader(scene.level.PlayerShader))
+	playerPos := scene.level.PlayerPos
+	if currentSave != nil && currentSave.Level == sceneName && currentSave.PlayerPos != (rl.Vector2{}) {
+		playerPos = currentSave.PlayerPos
+	}
+
+	scene.basePhysics = models.BasePhysics()
+	scene.player = models.NewPlayer(float32(playerPos.X), float32(playerPos.Y)).
+		WithShader(resources.GameShader(scene.level.PlayerShader)).
+		WithPhysics(scene.level.Physics.Apply(scene.basePhysics)).
+		WithDash(scene.level.Dash).
+		WithKillPlane(scene.bounds.Y + scene.bounds.Height + KILL_PLANE_MARGIN)
+	scene.player.CollisionProcessor = scene.collisions
+	scene.collisions.AddDynamicHitbox(scene.player.GetHitbox())
 
 	if scene.level.MusicTheme != "" {
-		scene.worldContainer.AddObjectResource(models.NewMusicStream(scene.level.MusicTheme, scene.level.MusicThemeReverse).SetRewindCollisionCheck(scene.player.IsCollisionRewind))
+		scene.music = models.NewMusicStream(scene.level.MusicTheme, scene.level.MusicThemeReverse).SetRewindCollisionCheck(scene.player.IsCollisionRewind)
+		scene.worldContainer.AddObjectResource(scene.music)
 	}
 
 	worldImages := scene.level.Images
@@ -70,10 +104,19 @@ func NewGameScene(sceneName string) *GameScene {
 	for i, _ := range collisionHitboxes {
 		hb := collisionHitboxes[i]
 		scene.worldContainer.AddObjectResource(&hb)
-		scene.player.CollisionProcessor.AddHitbox(&collision.Hitbox{
+		hitbox := &collision.Hitbox{
 			Polygons: hb.PolygonsWithRotation(),
 			Rotation: hb.Rotation,
-		})
+			OneWay:   hb.OneWay,
+			Layer:    hb.CollisionLayer(collision.LAYER_WORLD),
+			Material: hb.Material,
+		}
+		if hb.IsMoving() {
+			hb.StartMotion(hitbox)
+			scene.collisions.AddDynamicHitbox(hitbox)
+		} else {
+			scene.collisions.AddHitbox(hitbox)
+		}
 
 	}
 
@@ -87,8 +130,45 @@ func NewGameScene(sceneName string) *GameScene {
 	characters := scene.level.Characters
 	for i, _ := range characters {
 		npc := characters[i]
-		npc.CollisionProcessor.AddHitbox(scene.player.GetHitbox())
+		if currentSave != nil {
+			currentInteraction, ok := currentSave.Dialogues[npc.Id]
+			if ok && int(currentInteraction) < len(npc.Dialogues.Interactions) {
+				npc.Dialogues.CurrentInteraction = currentInteraction
+			}
+		}
+		npc.CollisionProcessor = scene.collisions
+		scene.collisions.AddHitbox(&collision.Hitbox{
+			Polygons: npc.PolygonsWithRotation(),
+			Layer:    npc.CollisionLayer(collision.LAYER_NPC),
+		})
 		scene.worldContainer.AddObjectResource(npc.ScreenChan(scene.onScreenQueue).ScreenScale(scene.screenScale))
+		scene.npcs = append(scene.npcs, &npc)
+	}
+
+	triggers := scene.level.Triggers
+	for i, _ := range triggers {
+		trigger := triggers[i]
+		trigger.CollisionProcessor = scene.collisions
+		scene.collisions.AddHitbox(&collision.Hitbox{
+			Polygons: trigger.PolygonsWithRotation(),
+			Layer:    trigger.CollisionLayer(collision.LAYER_TRIGGER),
+		})
+		scene.worldContainer.AddObjectResource(&trigger)
+		scene.triggers = append(scene.triggers, &trigger)
+	}
+
+	checkpoints := scene.level.Checkpoints
+	for i, _ := range checkpoints {
+		checkpoint := checkpoints[i]
+		checkpoint.CollisionProcessor = scene.collisions
+		scene.worldContainer.AddObjectResource(&checkpoint)
+		scene.checkpoints = append(scene.checkpoints, &checkpoint)
+	}
+
+	scene.restoreSavedCheckpoint()
+	if scene.checkpoint != nil && currentSave.PlayerPos == (rl.Vector2{}) { // start at checkpoint
+		scene.player.Pos = scene.checkpoint.PlayerPos
+		scene.restoreSpentTriggers()
 	}
 
 	scene.worldContainer.Sort()
@@ -105,7 +185,13 @@ func (s *GameScene) Run() models.Scene {
 		s.resume()
 	}
 
+	if restartFromCheckpoint {
+		restartFromCheckpoint = false
+		s.respawnAtCheckpoint()
+	}
+
 	nextScene := Menu
+	restart := false
 
 	for !rl.WindowShouldClose() {
 		rl.BeginDrawing()
@@ -114,6 +200,15 @@ func (s *GameScene) Run() models.Scene {
 		delta := rl.GetFrameTime()
 		s.camera.Zoom += rl.GetMouseWheelMove() * 0.05
 
+		if currentSave != nil {
+			currentSave.PlayTime += delta
+		}
+
+		s.levelTime += delta
+		if rl.IsKeyDown(rl.KeyLeftShift) {
+			s.rewindTime += delta
+		}
+
 		if rl.IsKeyDown(rl.KeyF1) { // jump to editor scene
 			nextScene = Editor
 			break
@@ -123,11 +218,18 @@ func (s *GameScene) Run() models.Scene {
 			models.DRAW_MODELS = !models.DRAW_MODELS
 		}
 
+		if rl.IsKeyReleased(rl.KeyF3) { // toggle physics tuning panel
+			s.physicsPanel = !s.physicsPanel
+		}
+
 		s.updateCamera(delta)
 
 		rl.BeginMode2D(*s.camera)
 		s.worldContainer.Update(delta)
 		s.worldContainer.Draw()
+		if models.DRAW_MODELS {
+			s.drawCollisionDebug()
+		}
 		rl.EndMode2D()
 
 		for len(s.onScreenQueue) > 0 {
@@ -135,12 +237,48 @@ func (s *GameScene) Run() models.Scene {
 			onScreenObject.Draw()
 			onScreenObject.Update(delta)
 		}
+
+		reached := models.PollReachedCheckpoints()
+		for i, _ := range reached {
+			s.activateCheckpoint(reached[i])
+		}
+
+		actions := models.PollTriggerActions()
+		for i, _ := range actions {
+			s.runTriggerAction(actions[i])
+		}
+
+		if s.triggerText != nil {
+			s.triggerText.Draw()
+			s.triggerText.Update(delta)
+		}
+
 		isWannaChangeScene, sc := models.IsWannaChangeScene()
 		if isWannaChangeScene {
 			nextScene = SceneId(sc)
+			if currentSave != nil && repository.IsNextLevel(s.level.Name, sc) {
+				currentSave.CompleteLevel(s.level.Name, s.levelTime, s.rewindTime)
+			}
+			s.autosave(sc, rl.Vector2{})
 			break
 		}
 
+		s.drawHealth()
+
+		if s.player.IsDead() && !rl.IsKeyDown(rl.KeyLeftShift) {
+			s.drawDeathOverlay()
+			if rl.IsKeyPressed(rl.KeyEnter) && s.checkpoint != nil {
+				s.respawnAtCheckpoint()
+			} else if rl.IsKeyPressed(rl.KeyEnter) {
+				restart = true
+				break
+			}
+		}
+
+		if s.physicsPanel {
+			s.drawPhysicsPanel()
+		}
+
 		if models.DRAW_MODELS {
 			models.NewText(10, 10).
 				SetFontSize(40).
@@ -154,19 +292,87 @@ func (s *GameScene) Run() models.Scene {
 
 	s.pause()
 
+	if restart {
+		return s.restart()
+	}
+
+	if nextScene == Menu {
+		playerPos := s.player.Pos
+		if s.player.IsDead() { // continue from level start
+			playerPos = rl.Vector2{}
+		}
+		s.autosave(s.level.Name, playerPos)
+	}
+
 	return GetScene(nextScene)
 }
 
+func (s *GameScene) autosave(level string, playerPos rl.Vector2) {
+	if currentSave == nil {
+		return
+	}
+	for i, _ := range s.npcs {
+		npc := s.npcs[i]
+		currentSave.Dialogues[npc.Id] = npc.Dialogues.CurrentInteraction
+	}
+	if currentSave.Level != level {
+		currentSave.ClearCheckpoint()
+	}
+	currentSave.Level = level
+	currentSave.PlayerPos = playerPos
+	writeCurrentSave()
+}
+
 func (s *GameScene) updateCamera(delta float32) {
-	cameraNewPos := s.player.Pos
-	cameraNewPos.Y = s.camera.Target.Y
+	focus := s.player.Pos
+	if s.cameraFocusTime > 0 { // held by trigger
+		focus = s.cameraFocus
+		s.cameraFocusTime -= delta
+	}
 
-	leftCameraLimit := WIDTH/2 + (WIDTH - (WIDTH*s.camera.Zoom))
-	if cameraNewPos.X < leftCameraLimit {
-		cameraNewPos.X = leftCameraLimit
+	if s.bounds.Width <= 0 || s.bounds.Height <= 0 { // empty level
+		updateCameraWithMode(s.camera, focus, delta)
+		return
 	}
 
+	s.clampCameraZoom()
+
+	cameraNewPos := s.clampCameraTarget(focus)
+
 	updateCameraWithMode(s.camera, cameraNewPos, delta)
+
+	s.camera.Target = s.clampCameraTarget(s.camera.Target)
+}
+
+// zoom out is limited so visible area never exceeds level bounds
+func (s *GameScene) clampCameraZoom() {
+	minZoom := float32(math.Max(float64(WIDTH/s.bounds.Width), float64(HEIGHT/s.bounds.Height)))
+	if s.camera.Zoom < minZoom {
+		s.camera.Zoom = minZoom
+	}
+}
+
+func (s *GameScene) clampCameraTarget(target rl.Vector2) rl.Vector2 {
+	halfWidth := s.camera.Offset.X / s.camera.Zoom
+	halfHeight := s.camera.Offset.Y / s.camera.Zoom
+
+	target.X = clampCameraAxis(target.X, s.bounds.X, s.bounds.Width, halfWidth)
+	target.Y = clampCameraAxis(target.Y, s.bounds.Y, s.bounds.Height, halfHeight)
+
+	return target
+}
+
+func clampCameraAxis(value, boundsStart, boundsSize, halfView float32) float32 {
+	if boundsSize <= halfView*2 {
+		return boundsStart + boundsSize/2
+	}
+	if value < boundsStart+halfView {
+		return boundsStart + halfView
+	}
+	if value > boundsStart+boundsSize-halfView {
+		return boundsStart + boundsSize - halfView
+	}
+	return value
 }
 
 func (m *GameScene) Unload() {
@@ -0,0 +1,81 @@
+package scene
+
+import (
+	"ahasuerus/models"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	DEFAULT_TRIGGER_DURATION = 3
+)
+
+func (s *GameScene) runTriggerAction(action models.FiredTriggerAction) {
+	duration := action.Duration
+	if duration <= 0 {
+		duration = DEFAULT_TRIGGER_DURATION
+	}
+	target := rl.Vector2Add(action.Origin, action.Offset)
+
+	switch action.Type {
+	case models.ChangeLevelAction:
+		if action.Value != "" {
+			models.ChangeSceneAsync(action.Value)
+		}
+	case models.StartMusicAction:
+		s.startMusic(action.Value, action.Reverse)
+	case models.SpawnParticlesAction:
+		s.spawnParticles(target, models.ParticleSourceType(action.Value))
+	case models.ShowTextAction:
+		s.triggerText = models.NewText(50, int32(HEIGHT)-150).
+			SetData(action.Value).
+			SetFontSize(50).
+			SetColor(rl.White).
+			WithExpireSeconds(duration, func(t *models.Text) {
+				if s.triggerText == t {
+					s.triggerText = nil
+				}
+			})
+	case models.SetFlagAction:
+		if action.Value != "" {
+			models.SetFlag(action.Value)
+		}
+	case models.CollectItemAction:
+		if action.Value != "" {
+			models.CollectItem(action.Value)
+		}
+	case models.MoveCameraAction:
+		s.cameraFocus = target
+		s.cameraFocusTime = duration
+	}
+}
+
+func (s *GameScene) startMusic(direct, reverse string) {
+	if direct == "" {
+		return
+	}
+	if reverse == "" {
+		reverse = direct
+	}
+
+	if s.music != nil {
+		s.music.Pause() // speaker stops reading streams before they are closed
+		s.music.Unload()
+		s.worldContainer.RemoveObjectResource(s.music)
+	}
+
+	s.music = models.NewMusicStream(direct, reverse).SetRewindCollisionCheck(s.player.IsCollisionRewind)
+	s.music.Load()
+	s.worldContainer.AddObjectResource(s.music)
+}
+
+func (s *GameScene) spawnParticles(pos rl.Vector2, particleType models.ParticleSourceType) {
+	if particleType == "" {
+		particleType = models.Bubble
+	}
+
+	ps := models.NewParticleSource(models.NewBaseEditorItem(models.RectanglePolygons(pos, 100, 100)))
+	ps.Type = particleType
+	ps.Load()
+	s.worldContainer.AddObjectResource(ps)
+}
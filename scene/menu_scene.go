@@ -4,8 +4,12 @@ import (
 	"ahasuerus/container"
 	"ahasuerus/controls"
 	"ahasuerus/models"
-	_ "ahasuerus/repository"
+	"ahasuerus/repository"
 	"ahasuerus/resources"
+	"ahasuerus/tiled"
+	"fmt"
+	"path/filepath"
+	"strings"
 
 	rg "github.com/gen2brain/raylib-go/raygui"
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -14,16 +18,37 @@ import (
 type MenuButton int
 
 const (
-	StartButton MenuButton = iota
+	ContinueButton MenuButton = iota
+	CheckpointButton
+	NewGameButton
+	LoadButton
+	LevelsButton
 	ExitButton
 )
 
+type MenuMode int
+
+const (
+	MainMenuMode MenuMode = iota
+	NewGameMenuMode
+	LoadMenuMode
+	LevelSelectMenuMode
+)
+
 type MenuScene struct {
 	menuContainer   *container.ObjectResourceContainer
 	menuShouldClose bool
 	nextScene       SceneId
 
-	currentButton MenuButton
+	currentButton    MenuButton
+	mode             MenuMode
+	currentSlot      int
+	slots            []repository.SaveGame
+	currentLevel     int
+	levels           []repository.LevelInfo
+	levelsSave       *repository.SaveGame
+	thumbnail        resources.GameTexture
+	thumbnailTexture rl.Texture2D
 
 	paused bool
 }
@@ -57,13 +82,29 @@ func (m *MenuScene) Run() models.Scene {
 		m.menuContainer.Update(delta)
 		m.menuContainer.Draw()
 
-		m.updateCurrentButton()
-
-		c := models.NewCounter()
-		m.drawButton("Start", StartButton, &c)
-		m.drawButton("Exit", ExitButton, &c)
-
-		m.processMenuEnter()
+		m.processDroppedFiles()
+
+		if m.mode == MainMenuMode {
+			m.updateCurrentButton()
+
+			c := models.NewCounter()
+			m.drawButton("Continue", ContinueButton, &c)
+			m.drawButton("Restart Checkpoint", CheckpointButton, &c)
+			m.drawButton("New Game", NewGameButton, &c)
+			m.drawButton("Load", LoadButton, &c)
+			m.drawButton("Levels", LevelsButton, &c)
+			m.drawButton("Exit", ExitButton, &c)
+
+			m.processMenuEnter()
+		} else if m.mode == LevelSelectMenuMode {
+			m.updateCurrentLevel()
+			m.drawLevels()
+			m.processLevelEnter()
+		} else {
+			m.updateCurrentSlot()
+			m.drawSlots()
+			m.processSlotEnter()
+		}
 		rl.EndDrawing()
 	}
 
@@ -83,6 +124,12 @@ func (m *MenuScene) drawButton(text string, button MenuButton, c *models.Counter
 	if m.currentButton == button {
 		color = rl.Orange
 	}
+	if button == ContinueButton && !m.canContinue() {
+		color = rl.Gray
+	}
+	if button == CheckpointButton && !m.canRestartCheckpoint() {
+		color = rl.Gray
+	}
 	models.DrawSdfText(text, rl.NewVector2(WIDTH/2-200, HEIGHT/10*float32(c.GetAndIncrement())), 100, color)
 }
 
@@ -96,8 +143,8 @@ func (m *MenuScene) updateCurrentButton() {
 		m.currentButton--
 	}
 
-	if m.currentButton < StartButton {
-		m.currentButton = StartButton
+	if m.currentButton < ContinueButton {
+		m.currentButton = ContinueButton
 	}
 
 	if m.currentButton > ExitButton {
@@ -113,12 +160,24 @@ func (m *MenuScene) processMenuEnter() {
 	}
 
 	if rl.IsKeyReleased(rl.KeyEnter) {
-		if m.currentButton == StartButton {
-			if lastScene == Menu {
-				lastScene = Start
-			}
-			m.menuShouldClose = true
-			m.nextScene = lastScene
+		if m.currentButton == ContinueButton {
+			m.processContinue()
+		}
+
+		if m.currentButton == CheckpointButton {
+			m.processRestartCheckpoint()
+		}
+
+		if m.currentButton == NewGameButton {
+			m.openSlots(NewGameMenuMode)
+		}
+
+		if m.currentButton == LoadButton {
+			m.openSlots(LoadMenuMode)
+		}
+
+		if m.currentButton == LevelsButton {
+			m.openLevels()
 		}
 
 		if m.currentButton == ExitButton {
@@ -128,6 +187,341 @@ func (m *MenuScene) processMenuEnter() {
 	}
 }
 
+func (m *MenuScene) canContinue() bool {
+	if currentSave != nil {
+		return true
+	}
+	_, ok := repository.GetLatestSave()
+	return ok
+}
+
+func (m *MenuScene) canRestartCheckpoint() bool {
+	return currentSave != nil && currentSave.Checkpoint != ""
+}
+
+// processRestartCheckpoint returns to level of current save and respawns at its checkpoint
+func (m *MenuScene) processRestartCheckpoint() {
+	if !m.canRestartCheckpoint() {
+		return
+	}
+	currentSave.PlayerPos = rl.Vector2{}
+	restartFromCheckpoint = true
+	m.menuShouldClose = true
+	m.nextScene = SceneId(currentSave.Level)
+}
+
+func (m *MenuScene) processContinue() {
+	if currentSave != nil && lastScene != Menu { // resume level from memory
+		m.menuShouldClose = true
+		m.nextScene = lastScene
+		return
+	}
+
+	save, ok := repository.GetLatestSave()
+	if ok {
+		m.menuShouldClose = true
+		m.nextScene = startSave(save)
+	}
+}
+
+func (m *MenuScene) openSlots(mode MenuMode) {
+	m.mode = mode
+	m.currentSlot = 0
+	m.slots = repository.GetSaves()
+}
+
+func (m *MenuScene) updateCurrentSlot() {
+	if rl.IsKeyReleased(rl.KeyDown) {
+		m.currentSlot++
+	}
+
+	if rl.IsKeyReleased(rl.KeyUp) {
+		m.currentSlot--
+	}
+
+	// last row is "Back"
+	if m.currentSlot < 0 {
+		m.currentSlot = 0
+	}
+
+	if m.currentSlot > len(m.slots) {
+		m.currentSlot = len(m.slots)
+	}
+}
+
+func (m *MenuScene) drawSlots() {
+	title := "New Game"
+	if m.mode == LoadMenuMode {
+		title = "Load"
+	}
+	models.DrawSdfText(title, rl.NewVector2(WIDTH/2-200, HEIGHT/10), 100, rl.Purple)
+
+	c := models.NewCounter()
+	c.GetAndIncrement()
+	for i, _ := range m.slots {
+		save := m.slots[i]
+		text := fmt.Sprintf("Slot %d: empty", save.Slot)
+		if !save.IsEmpty() {
+			text = fmt.Sprintf("Slot %d: %s %s", save.Slot, save.Level, save.PlayTimeString())
+		}
+		m.drawSlotRow(text, i, &c)
+	}
+	m.drawSlotRow("Back", len(m.slots), &c)
+}
+
+func (m *MenuScene) drawSlotRow(text string, row int, c *models.Counter) {
+	color := rl.White
+	if m.currentSlot == row {
+		color = rl.Orange
+	}
+	if m.mode == LoadMenuMode && row < len(m.slots) && m.slots[row].IsEmpty() {
+		color = rl.Gray
+	}
+	models.DrawSdfText(text, rl.NewVector2(WIDTH/2-400, HEIGHT/10*float32(c.GetAndIncrement())), 70, color)
+}
+
+func (m *MenuScene) processSlotEnter() {
+	m.menuShouldClose = rl.WindowShouldClose()
+	if m.menuShouldClose {
+		m.nextScene = Close
+	}
+
+	if !rl.IsKeyReleased(rl.KeyEnter) {
+		return
+	}
+
+	if m.currentSlot == len(m.slots) {
+		m.mode = MainMenuMode
+		return
+	}
+
+	slot := m.slots[m.currentSlot]
+
+	if m.mode == NewGameMenuMode {
+		save := repository.NewSaveGame(slot.Slot, repository.GetFirstLevel().Name)
+		save.Save()
+		m.mode = MainMenuMode
+		m.menuShouldClose = true
+		m.nextScene = startSave(save)
+	}
+
+	if m.mode == LoadMenuMode && !slot.IsEmpty() {
+		m.mode = MainMenuMode
+		m.menuShouldClose = true
+		m.nextScene = startSave(slot)
+	}
+}
+
+func (m *MenuScene) openLevels() {
+	m.mode = LevelSelectMenuMode
+	m.currentLevel = 0
+	m.levels = repository.GetLevels()
+
+	m.levelsSave = currentSave
+	if m.levelsSave == nil {
+		latest, ok := repository.GetLatestSave()
+		if ok {
+			m.levelsSave = &latest
+		}
+	}
+}
+
+func (m *MenuScene) isLevelUnlocked(level repository.LevelInfo) bool {
+	if !level.Locked {
+		return true
+	}
+	return m.levelsSave != nil && m.levelsSave.IsLevelUnlocked(level.Name)
+}
+
+func (m *MenuScene) updateCurrentLevel() {
+	if rl.IsKeyReleased(rl.KeyDown) {
+		m.currentLevel++
+	}
+
+	if rl.IsKeyReleased(rl.KeyUp) {
+		m.currentLevel--
+	}
+
+	// last row is "Back"
+	if m.currentLevel < 0 {
+		m.currentLevel = 0
+	}
+
+	if m.currentLevel > len(m.levels) {
+		m.currentLevel = len(m.levels)
+	}
+}
+
+func (m *MenuScene) drawLevels() {
+	models.DrawSdfText("Levels", rl.NewVector2(WIDTH/2-200, HEIGHT/10), 100, rl.Purple)
+
+	c := models.NewCounter()
+	c.GetAndIncrement()
+	for i, _ := range m.levels {
+		level := m.levels[i]
+		text := level.Title
+		color := rl.White
+		if !m.isLevelUnlocked(level) {
+			text += " (locked)"
+			color = rl.Gray
+		}
+		if m.currentLevel == i {
+			color = rl.Orange
+		}
+		models.DrawSdfText(text, rl.NewVector2(WIDTH/2-400, HEIGHT/10*float32(c.GetAndIncrement())), 70, color)
+	}
+
+	color := rl.White
+	if m.currentLevel == len(m.levels) {
+		color = rl.Orange
+	}
+	models.DrawSdfText("Back", rl.NewVector2(WIDTH/2-400, HEIGHT/10*float32(c.GetAndIncrement())), 70, color)
+
+	if m.currentLevel < len(m.levels) {
+		m.drawLevelDetails(m.levels[m.currentLevel])
+	}
+}
+
+func (m *MenuScene) drawLevelDetails(level repository.LevelInfo) {
+	posX := WIDTH/2 + 200
+	posY := HEIGHT / 5
+
+	m.updateThumbnail(resources.GameTexture(level.Thumbnail))
+	if m.thumbnail != "" {
+		texture := m.thumbnailTexture
+		scale := (WIDTH/2 - 300) / float32(texture.Width)
+		rl.DrawTextureEx(texture, rl.NewVector2(posX, posY), 0, scale, rl.White)
+		posY += float32(texture.Height)*scale + 20
+	}
+
+	rows := []string{}
+	if level.Author != "" {
+		rows = append(rows, "by "+level.Author)
+	}
+	if level.Description != "" {
+		rows = append(rows, level.Description)
+	}
+	if level.ParTime > 0 {
+		rows = append(rows, "par time "+repository.FormatTime(level.ParTime))
+	}
+	if level.RewindBudget > 0 {
+		rows = append(rows, fmt.Sprintf("rewind budget %.1fs", level.RewindBudget))
+	}
+	if m.levelsSave != nil {
+		completion, ok := m.levelsSave.Completions[level.Name]
+		if ok {
+			rows = append(rows, "best time "+repository.FormatTime(completion.BestTime))
+			rows = append(rows, fmt.Sprintf("best rewind %.1fs", completion.BestRewind))
+		}
+	}
+
+	for i, _ := range rows {
+		models.DrawSdfText(rows[i], rl.NewVector2(posX, posY+float32(i*50)), 45, rl.White)
+	}
+}
+
+// updateThumbnail loads preview outside of shared texture cache,
+// unloading it must not take texture from loaded level using the same image
+func (m *MenuScene) updateThumbnail(thumbnail resources.GameTexture) {
+	if m.thumbnail == thumbnail {
+		return
+	}
+	if m.thumbnail != "" {
+		rl.UnloadTexture(m.thumbnailTexture)
+	}
+	m.thumbnail = thumbnail
+	if m.thumbnail != "" {
+		m.thumbnailTexture = rl.LoadTexture(string(m.thumbnail))
+	}
+}
+
+func (m *MenuScene) processLevelEnter() {
+	m.menuShouldClose = rl.WindowShouldClose()
+	if m.menuShouldClose {
+		m.nextScene = Close
+	}
+
+	if !rl.IsKeyReleased(rl.KeyEnter) {
+		return
+	}
+
+	if m.currentLevel == len(m.levels) {
+		m.mode = MainMenuMode
+		m.updateThumbnail("")
+		return
+	}
+
+	level := m.levels[m.currentLevel]
+	if !m.isLevelUnlocked(level) {
+		return
+	}
+
+	var save repository.SaveGame
+	if m.levelsSave != nil {
+		save = *m.levelsSave
+	} else if slot, ok := repository.FreeSaveSlot(); ok { // never overwrite other slot
+		save = repository.NewSaveGame(slot, level.Name)
+	} else {
+		m.showMessage("no free save slot, load a game first")
+		return
+	}
+	save.Level = level.Name
+	save.PlayerPos = rl.Vector2{}
+
+	m.mode = MainMenuMode
+	m.updateThumbnail("")
+	m.menuShouldClose = true
+	m.nextScene = startSave(save)
+}
+
+func (m *MenuScene) processDroppedFiles() {
+	if !rl.IsFileDropped() {
+		return
+	}
+
+	files := rl.LoadDroppedFiles()
+
+	for _, file := range files {
+		ext := strings.ToLower(filepath.Ext(file))
+
+		if ext == ".zip" {
+			levelName, err := repository.ImportLevelPack(file)
+			if err != nil {
+				m.showMessage("import failed: " + err.Error())
+			} else {
+				m.showMessage("imported level " + levelName)
+			}
+		}
+
+		if ext == ".tmx" || ext == ".tmj" || ext == ".json" {
+			level, err := tiled.ImportFile(file)
+			if err != nil {
+				m.showMessage("tiled import failed: " + err.Error())
+			} else {
+				level.Name = repository.FreeLevelName(level.Name)
+				level.SaveLevel()
+				m.showMessage("imported tiled map " + level.Name)
+			}
+		}
+	}
+
+	if m.mode == LevelSelectMenuMode {
+		m.levels = repository.GetLevels()
+	}
+}
+
+func (m *MenuScene) showMessage(message string) {
+	m.menuContainer.AddObject(
+		models.NewText(50, int32(HEIGHT)-100).
+			SetData(message).
+			SetFontSize(40).
+			SetColor(rl.White).
+			WithExpire(5, func(t *models.Text) {
+				m.menuContainer.RemoveObject(t)
+			}),
+	)
+}
+
 func (m *MenuScene) Unload() {
 	m.menuContainer.Unload()
 }
@@ -0,0 +1,32 @@
+package scene
+
+import (
+	"ahasuerus/models"
+	"ahasuerus/repository"
+)
+
+var (
+	currentSave *repository.SaveGame
+)
+
+func startSave(save repository.SaveGame) SceneId {
+	currentSave = &save
+	models.ResetCollectedItems(save.CollectedItems)
+	models.ResetFlags(save.Flags)
+
+	// drop cached level so it is rebuilt with save state
+	if _, ok := sceneMap[SceneId(save.Level)]; ok {
+		UnloadScene(SceneId(save.Level))
+	}
+
+	return SceneId(save.Level)
+}
+
+func writeCurrentSave() {
+	if currentSave == nil {
+		return
+	}
+	currentSave.CollectedItems = models.GetCollectedItems()
+	currentSave.Flags = models.GetFlags()
+	currentSave.Save()
+}
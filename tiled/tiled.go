@@ -0,0 +1,326 @@
+package tiled
+
+import (
+	"ahasuerus/collision"
+	"ahasuerus/models"
+	"ahasuerus/particle"
+	"ahasuerus/repository"
+	"ahasuerus/resources"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+	"github.com/google/uuid"
+)
+
+// Object types recognized in object layers (Tiled "class" or "type")
+const (
+	CollisionType  = "collision"
+	LightType      = "light"
+	ParticlesType  = "particles"
+	NpcType        = "npc"
+	PlayerType     = "player"
+	CheckpointType = "checkpoint"
+)
+
+type Map struct {
+	Type         string     `json:"type"`         // "map" in Tiled JSON
+	TiledVersion string     `json:"tiledversion"` // editor version which saved map
+	Layers       []Layer    `json:"layers"`
+	Properties   []Property `json:"properties"`
+}
+
+type Layer struct {
+	Type       string     `json:"type"`
+	Name       string     `json:"name"`
+	Image      string     `json:"image"`
+	OffsetX    float32    `json:"offsetx"`
+	OffsetY    float32    `json:"offsety"`
+	ParallaxX  *float32   `json:"parallaxx"`
+	Objects    []Object   `json:"objects"`
+	Layers     []Layer    `json:"layers"`
+	Properties []Property `json:"properties"`
+}
+
+type Object struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Class      string     `json:"class"`
+	X          float32    `json:"x"`
+	Y          float32    `json:"y"`
+	Width      float32    `json:"width"`
+	Height     float32    `json:"height"`
+	Rotation   float32    `json:"rotation"`
+	Point      bool       `json:"point"`
+	Polygon    []Point    `json:"polygon"`
+	Properties []Property `json:"properties"`
+}
+
+type Point struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+type Property struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// ImportFile reads Tiled JSON (.tmj, .json) or TMX map and converts it to level
+func ImportFile(path string) (repository.Level, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return repository.Level{}, err
+	}
+
+	var m Map
+	if strings.EqualFold(filepath.Ext(path), ".tmx") {
+		m, err = parseTmx(data)
+	} else {
+		err = json.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return repository.Level{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if m.Type != "map" && m.TiledVersion == "" {
+		return repository.Level{}, fmt.Errorf("%s is not a Tiled map", filepath.Base(path))
+	}
+
+	level := m.ToLevel(filepath.Dir(path))
+	level.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return level, nil
+}
+
+func (m Map) ToLevel(mapDir string) repository.Level {
+	level := repository.Level{
+		Characters:         []models.Npc{},
+		Lights:             []models.Light{},
+		CollissionHitboxes: []models.CollisionHitbox{},
+		Images:             []models.Image{},
+		ParticleSources:    []models.ParticleSource{},
+	}
+
+	props := properties(m.Properties)
+	level.Title = props["title"]
+	level.Author = props["author"]
+	level.Description = props["description"]
+	level.Order, _ = strconv.Atoi(props["order"])
+	level.PlayerShader = props["playershader"]
+	level.MusicTheme = props["music"]
+	level.MusicThemeReverse = props["musicreverse"]
+
+	c := converter{level: &level, mapDir: mapDir}
+	c.layers(m.Layers, rl.Vector2{})
+
+	return level
+}
+
+type converter struct {
+	level     *repository.Level
+	mapDir    string
+	drawIndex int
+}
+
+func (c *converter) layers(layers []Layer, offset rl.Vector2) {
+	for i, _ := range layers {
+		layer := layers[i]
+		layerOffset := rl.Vector2Add(offset, rl.NewVector2(layer.OffsetX, layer.OffsetY))
+
+		switch layer.Type {
+		case "imagelayer":
+			c.imageLayer(layer, layerOffset)
+		case "objectgroup":
+			c.objectLayer(layer, layerOffset)
+		case "group":
+			c.layers(layer.Layers, layerOffset)
+		}
+	}
+}
+
+func (c *converter) imageLayer(layer Layer, offset rl.Vector2) {
+	if layer.Image == "" {
+		return
+	}
+
+	image := models.NewImage(uuid.NewString(), resources.GameTexture(c.resourcePath(layer.Image)), offset.X, offset.Y, 0)
+
+	// size is known only after texture load, read it from file for level bounds
+	width, height, err := imageSize(filepath.Join(c.mapDir, layer.Image))
+	if err == nil {
+		image.SetPolygons(models.RectanglePolygons(offset, width, height))
+	}
+
+	// tiled parallax 1 means moving together with camera
+	if layer.ParallaxX != nil && *layer.ParallaxX != 1 {
+		parallax := 1 - *layer.ParallaxX
+		if parallax < 0 {
+			parallax = -parallax
+		}
+		image.Parallax = parallax
+	}
+
+	// first layers are drawn first, so they are further from camera
+	image.DrawIndex = c.drawIndex
+	c.drawIndex--
+
+	c.level.Images = append(c.level.Images, *image)
+}
+
+func (c *converter) objectLayer(layer Layer, offset rl.Vector2) {
+	for i, _ := range layer.Objects {
+		obj := layer.Objects[i]
+		objType := strings.ToLower(obj.Class)
+		if objType == "" {
+			objType = strings.ToLower(obj.Type)
+		}
+
+		pos := rl.Vector2Add(offset, rl.NewVector2(obj.X, obj.Y))
+
+		if obj.Point || objType == PlayerType {
+			if strings.EqualFold(obj.Name, PlayerType) || objType == PlayerType {
+				c.level.PlayerPos = pos
+			} else {
+				fmt.Println("WARN: Tiled point object skipped, only player spawn is supported:", obj.Name)
+			}
+			continue
+		}
+
+		props := properties(obj.Properties)
+		bei := models.NewBaseEditorItem(c.objectPolygons(obj, pos))
+		bei.Rotation = obj.Rotation
+		layer, _ := collision.ParseLayer(props["layer"]) // unknown layer keeps object default
+
+		switch objType {
+		case LightType:
+			c.level.Lights = append(c.level.Lights, models.Light{BaseEditorItem: bei})
+		case ParticlesType:
+			ps := models.NewParticleSource(bei)
+			if props["type"] != "" {
+				ps.Type = models.ParticleSourceType(props["type"])
+			}
+			if props["texture"] != "" {
+				ps.ParticleTexture = resources.GameTexture(c.resourcePath(props["texture"]))
+			}
+			ps.SystemSettings = particle.DefaultParticleSystemSettings()
+			c.level.ParticleSources = append(c.level.ParticleSources, *ps)
+		case CheckpointType:
+			c.level.Checkpoints = append(c.level.Checkpoints, models.Checkpoint{
+				CollisionHitbox: models.CollisionHitbox{BaseEditorItem: bei},
+			})
+		case NpcType:
+			npc := c.npc(obj, props, bei)
+			npc.Layer = layer
+			c.level.Characters = append(c.level.Characters, npc)
+		default:
+			hitbox := models.CollisionHitbox{
+				BaseEditorItem: bei,
+				OneWay:         props["oneway"] == "true",
+				Layer:          layer,
+			}
+			if material, ok := collision.MaterialByName(props["material"]); ok {
+				hitbox.Material = &material
+			}
+			if len(obj.Polygon) >= 3 {
+				hitbox.SetShapePoints(polygonPoints(obj, pos))
+				hitbox.Rotation = 0 // rotation is applied to polygon points
+			}
+			c.level.CollissionHitboxes = append(c.level.CollissionHitboxes, hitbox)
+		}
+	}
+}
+
+func (c *converter) npc(obj Object, props map[string]string, bei models.BaseEditorItem) models.Npc {
+	npc := models.Npc{
+		CollisionHitbox: models.CollisionHitbox{BaseEditorItem: bei},
+		Dialogues: models.NpcDialog{
+			CharacterName: obj.Name,
+			LevelJump:     props["level"],
+		},
+	}
+	if props["text"] != "" {
+		npc.Dialogues.Interactions = []models.NpcInteraction{
+			{
+				Text:    props["text"],
+				Options: []string{"Ok"},
+				Routes:  []uint{0},
+			},
+		}
+	}
+	if props["bgimage"] != "" {
+		npc.BgImagePath = c.resourcePath(props["bgimage"])
+	}
+	return npc
+}
+
+// polygonPoints returns polygon object points in level coordinates, rotated around object position like in Tiled
+func polygonPoints(obj Object, pos rl.Vector2) []rl.Vector2 {
+	points := make([]rl.Vector2, len(obj.Polygon))
+	for i, point := range obj.Polygon {
+		points[i] = rl.Vector2Add(pos, models.Vec2Rotate(rl.NewVector2(point.X, point.Y), float64(obj.Rotation)))
+	}
+	return points
+}
+
+// objectPolygons returns bounding rectangle of object before rotation, collision polygons keep their outline in hitbox shape
+func (c *converter) objectPolygons(obj Object, pos rl.Vector2) [2]collision.Polygon {
+	width := obj.Width
+	height := obj.Height
+	topLeft := pos
+
+	if len(obj.Polygon) > 0 {
+		min := rl.NewVector2(obj.Polygon[0].X, obj.Polygon[0].Y)
+		max := min
+		for _, point := range obj.Polygon {
+			min.X = float32(math.Min(float64(min.X), float64(point.X)))
+			min.Y = float32(math.Min(float64(min.Y), float64(point.Y)))
+			max.X = float32(math.Max(float64(max.X), float64(point.X)))
+			max.Y = float32(math.Max(float64(max.Y), float64(point.Y)))
+		}
+		// Tiled rotates object around its origin, editor around top left of bounds
+		topLeft = rl.Vector2Add(pos, models.Vec2Rotate(min, float64(obj.Rotation)))
+		width = max.X - min.X
+		height = max.Y - min.Y
+	}
+
+	return models.RectanglePolygons(topLeft, width, height)
+}
+
+// resourcePath converts path relative to map into path relative to game directory
+func (c *converter) resourcePath(path string) string {
+	full := filepath.ToSlash(filepath.Clean(filepath.Join(c.mapDir, path)))
+	parts := strings.SplitN(full, "resources/", 2)
+	if len(parts) == 2 {
+		return "resources/" + parts[1]
+	}
+	return full
+}
+
+func imageSize(path string) (float32, float32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, err
+	}
+	return float32(config.Width), float32(config.Height), nil
+}
+
+func properties(props []Property) map[string]string {
+	result := make(map[string]string)
+	for _, prop := range props {
+		result[strings.ToLower(prop.Name)] = fmt.Sprint(prop.Value)
+	}
+	return result
+}
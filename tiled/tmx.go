@@ -0,0 +1,134 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+type tmxMap struct {
+	XMLName      xml.Name
+	TiledVersion string        `xml:"tiledversion,attr"`
+	Properties   []tmxProperty `xml:"properties>property"`
+	Layers       []tmxLayer    `xml:",any"`
+}
+
+// tmxLayer covers imagelayer, objectgroup and group elements
+type tmxLayer struct {
+	XMLName    xml.Name
+	Name       string        `xml:"name,attr"`
+	OffsetX    float32       `xml:"offsetx,attr"`
+	OffsetY    float32       `xml:"offsety,attr"`
+	ParallaxX  *float32      `xml:"parallaxx,attr"`
+	Image      tmxImage      `xml:"image"`
+	Objects    []tmxObject   `xml:"object"`
+	Layers     []tmxLayer    `xml:",any"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+}
+
+type tmxObject struct {
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float32       `xml:"x,attr"`
+	Y          float32       `xml:"y,attr"`
+	Width      float32       `xml:"width,attr"`
+	Height     float32       `xml:"height,attr"`
+	Rotation   float32       `xml:"rotation,attr"`
+	Point      *struct{}     `xml:"point"`
+	Polygon    *tmxPolygon   `xml:"polygon"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+type tmxPolygon struct {
+	Points string `xml:"points,attr"`
+}
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"`
+}
+
+func parseTmx(data []byte) (Map, error) {
+	var tm tmxMap
+	err := xml.Unmarshal(data, &tm)
+	if err != nil {
+		return Map{}, err
+	}
+	mapType := ""
+	if tm.XMLName.Local == "map" {
+		mapType = "map"
+	}
+	return Map{
+		Type:         mapType,
+		TiledVersion: tm.TiledVersion,
+		Layers:       convertTmxLayers(tm.Layers),
+		Properties:   convertTmxProperties(tm.Properties),
+	}, nil
+}
+
+func convertTmxLayers(tmxLayers []tmxLayer) []Layer {
+	layers := make([]Layer, 0)
+	for _, tl := range tmxLayers {
+		layer := Layer{
+			Type:       tl.XMLName.Local,
+			Name:       tl.Name,
+			Image:      tl.Image.Source,
+			OffsetX:    tl.OffsetX,
+			OffsetY:    tl.OffsetY,
+			ParallaxX:  tl.ParallaxX,
+			Layers:     convertTmxLayers(tl.Layers),
+			Properties: convertTmxProperties(tl.Properties),
+		}
+		for _, to := range tl.Objects {
+			layer.Objects = append(layer.Objects, convertTmxObject(to))
+		}
+		layers = append(layers, layer)
+	}
+	return layers
+}
+
+func convertTmxObject(to tmxObject) Object {
+	obj := Object{
+		Name:       to.Name,
+		Type:       to.Type,
+		Class:      to.Class,
+		X:          to.X,
+		Y:          to.Y,
+		Width:      to.Width,
+		Height:     to.Height,
+		Rotation:   to.Rotation,
+		Point:      to.Point != nil,
+		Properties: convertTmxProperties(to.Properties),
+	}
+	if to.Polygon != nil {
+		for _, pair := range strings.Fields(to.Polygon.Points) {
+			coords := strings.Split(pair, ",")
+			if len(coords) != 2 {
+				continue
+			}
+			x, _ := strconv.ParseFloat(coords[0], 32)
+			y, _ := strconv.ParseFloat(coords[1], 32)
+			obj.Polygon = append(obj.Polygon, Point{X: float32(x), Y: float32(y)})
+		}
+	}
+	return obj
+}
+
+func convertTmxProperties(tmxProps []tmxProperty) []Property {
+	props := make([]Property, 0)
+	for _, tp := range tmxProps {
+		value := tp.Value
+		if value == "" {
+			value = strings.TrimSpace(tp.Text) // multiline strings are stored as element text
+		}
+		props = append(props, Property{Name: tp.Name, Type: tp.Type, Value: value})
+	}
+	return props
+}